@@ -0,0 +1,86 @@
+package helmet
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HeaderClearSiteData is the Clear-Site-Data HTTP security header.
+const HeaderClearSiteData = "Clear-Site-Data"
+
+// List of all Clear-Site-Data directives.
+const (
+	DirectiveCache             ClearSiteDataDirective = `"cache"`
+	DirectiveCookies           ClearSiteDataDirective = `"cookies"`
+	DirectiveStorage           ClearSiteDataDirective = `"storage"`
+	DirectiveExecutionContexts ClearSiteDataDirective = `"executionContexts"`
+	DirectiveClearAll          ClearSiteDataDirective = `"*"`
+)
+
+type (
+	// ClearSiteDataDirective represents a Clear-Site-Data directive.
+	ClearSiteDataDirective string
+
+	// ClearSiteData represents the Clear-Site-Data HTTP security header.
+	// Unlike the other headers it is only sent on requests to specific paths, such as a logout endpoint.
+	ClearSiteData struct {
+		// Request paths, matched exactly, that the header is sent on.
+		Paths []string
+
+		directives []ClearSiteDataDirective
+
+		cache string
+	}
+)
+
+// NewClearSiteData creates a new Clear-Site-Data that is sent on requests to the given paths.
+func NewClearSiteData(paths []string, directives ...ClearSiteDataDirective) *ClearSiteData {
+	csd := &ClearSiteData{paths, []ClearSiteDataDirective{}, ""}
+	for _, directive := range directives {
+		csd.directives = append(csd.directives, directive)
+	}
+	return csd
+}
+
+// EmptyClearSiteData creates a blank slate Clear-Site-Data.
+func EmptyClearSiteData() *ClearSiteData {
+	return NewClearSiteData(nil)
+}
+
+// Name returns the Clear-Site-Data HTTP security header.
+func (csd *ClearSiteData) Name() string {
+	return HeaderClearSiteData
+}
+
+func (csd *ClearSiteData) String() string {
+	if csd.cache != "" {
+		return csd.cache
+	}
+
+	directivesAsStrings := []string{}
+	for _, directive := range csd.directives {
+		directivesAsStrings = append(directivesAsStrings, string(directive))
+	}
+
+	csd.cache = strings.Join(directivesAsStrings, ", ")
+	return csd.cache
+}
+
+// Empty returns whether the Clear-Site-Data is empty.
+func (csd *ClearSiteData) Empty() bool {
+	return len(csd.directives) == 0 || len(csd.Paths) == 0
+}
+
+// Header adds the Clear-Site-Data HTTP security header to the given http.ResponseWriter if the http.Request is for one of its paths.
+func (csd *ClearSiteData) Header(w http.ResponseWriter, r *http.Request) {
+	if csd.Empty() || r == nil || r.URL == nil {
+		return
+	}
+
+	for _, path := range csd.Paths {
+		if r.URL.Path == path {
+			w.Header().Set(HeaderClearSiteData, csd.String())
+			return
+		}
+	}
+}
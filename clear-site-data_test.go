@@ -0,0 +1,119 @@
+package helmet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClearSiteData_New(t *testing.T) {
+	t.Parallel()
+
+	csd := NewClearSiteData([]string{"/logout"}, DirectiveCache, DirectiveCookies)
+
+	if len(csd.Paths) != 1 || csd.Paths[0] != "/logout" {
+		t.Errorf("Incorrect Paths\tExpected: %v\tActual: %v\n", []string{"/logout"}, csd.Paths)
+	}
+
+	if len(csd.directives) != 2 {
+		t.Errorf("Incorrect amount of directives\tExpected: %d\tActual: %d\n", 2, len(csd.directives))
+	}
+
+	if csd.cache != "" {
+		t.Errorf("Cache should not be set\tActual: %s\n", csd.cache)
+	}
+}
+
+func TestClearSiteData_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		clearSiteData  *ClearSiteData
+		expectedHeader string
+	}{
+		{name: "Empty", clearSiteData: EmptyClearSiteData(), expectedHeader: ""},
+		{name: "Single Directive", clearSiteData: NewClearSiteData(nil, DirectiveClearAll), expectedHeader: `"*"`},
+		{
+			name:           "Multiple Directives",
+			clearSiteData:  NewClearSiteData(nil, DirectiveCache, DirectiveCookies, DirectiveStorage, DirectiveExecutionContexts),
+			expectedHeader: `"cache", "cookies", "storage", "executionContexts"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := tc.clearSiteData.String()
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+
+			// utilize said cache
+			header = tc.clearSiteData.String()
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+}
+
+func TestClearSiteData_Empty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		clearSiteData *ClearSiteData
+		expectedEmpty bool
+	}{
+		{name: "Empty", clearSiteData: EmptyClearSiteData(), expectedEmpty: true},
+		{name: "No Paths", clearSiteData: NewClearSiteData(nil, DirectiveCookies), expectedEmpty: true},
+		{name: "No Directives", clearSiteData: NewClearSiteData([]string{"/logout"}), expectedEmpty: true},
+		{name: "Paths, Directives", clearSiteData: NewClearSiteData([]string{"/logout"}, DirectiveCookies), expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.clearSiteData.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
+
+func TestClearSiteData_Header(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedHeader string
+	}{
+		{name: "Matching Path", path: "/logout", expectedHeader: `"cookies", "storage"`},
+		{name: "Other Path", path: "/", expectedHeader: ""},
+		{name: "Path Prefix", path: "/logout/now", expectedHeader: ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csd := NewClearSiteData([]string{"/logout"}, DirectiveCookies, DirectiveStorage)
+
+			rr := httptest.NewRecorder()
+			csd.Header(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			header := rr.Result().Header.Get(HeaderClearSiteData)
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Command cspdiff reports the semantic differences between two Content-Security-Policy HTTP header values.
+//
+// Usage:
+//
+//	cspdiff [-loosened-exit-code 1] <before> <after>
+//
+// Either argument may be @path to read the header value from a file. cspdiff exits with the given
+// exit code if the change loosens security, so that it can guard deploys.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/goddtriffin/helmet"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("cspdiff: ")
+
+	loosenedExitCode := flag.Int("loosened-exit-code", 0, "exit code to use if the change loosens security")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: cspdiff [-loosened-exit-code code] <before> <after>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	before, err := readHeader(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	after, err := readHeader(flag.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	diff := helmet.DiffContentSecurityPolicyHeaders(before, after)
+	fmt.Println(diff)
+
+	switch diff.Change() {
+	case helmet.CSPLoosened, helmet.CSPMixed:
+		os.Exit(*loosenedExitCode)
+	}
+}
+
+// readHeader returns the argument, or the contents of the file it names if it starts with @.
+func readHeader(arg string) (string, error) {
+	if !strings.HasPrefix(arg, "@") {
+		return arg, nil
+	}
+
+	contents, err := os.ReadFile(strings.TrimPrefix(arg, "@"))
+	if err != nil {
+		return "", err
+	}
+	return string(contents), nil
+}
@@ -9,6 +9,9 @@ import (
 // HeaderContentSecurityPolicy is the Content-Security-Policy HTTP security header.
 const HeaderContentSecurityPolicy = "Content-Security-Policy"
 
+// HeaderContentSecurityPolicyReportOnly is the Content-Security-Policy-Report-Only HTTP security header.
+const HeaderContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
+
 // List of all Content-Security-Policy Fetch directives.
 const (
 	DirectiveChildSrc      CSPDirective = "child-src"
@@ -79,6 +82,7 @@ const (
 	SourceUnsafeEval           CSPSource = "'unsafe-eval'"
 	SourceUnsafeHashes         CSPSource = "'unsafe-hashes'"
 	SourceUnsafeInline         CSPSource = "'unsafe-inline'"
+	SourceWasmUnsafeEval       CSPSource = "'wasm-unsafe-eval'"
 	SourceStrictDynamic        CSPSource = "'strict-dynamic'"
 	SourceReportSample         CSPSource = "'report-sample'"
 )
@@ -109,11 +113,18 @@ const (
 	SandboxAllowStorageAccessByUserActivatation CSPSource = "allow-storage-access-by-user-activation"
 	SandboxAllowTopNavigation                   CSPSource = "allow-top-navigation"
 	SandboxAllowTopNavigationByUserActivation   CSPSource = "allow-top-navigation-by-user-activation"
+	SandboxAllowTopNavigationToCustomProtocols  CSPSource = "allow-top-navigation-to-custom-protocols"
 )
 
 // List of all DirectiveTrustedTypes values.
 const (
-	TrustedTypesAllowDuplicates CSPSource = "allow-duplicates"
+	// TrustedTypesAllowDuplicates is quoted, as a keyword, since earlier releases sent it as a policy name by mistake.
+	TrustedTypesAllowDuplicates CSPSource = "'allow-duplicates'"
+)
+
+// List of all DirectiveRequireTrustedTypesFor values.
+const (
+	RequireTrustedTypesForScript CSPSource = "'script'"
 )
 
 type (
@@ -126,6 +137,7 @@ type (
 	// ContentSecurityPolicy represents the Content-Security-Policy HTTP security header.
 	ContentSecurityPolicy struct {
 		policies map[CSPDirective][]CSPSource
+		nonces   []CSPDirective // directives that get a per-request nonce
 
 		cache string
 	}
@@ -136,7 +148,7 @@ func NewContentSecurityPolicy(policies map[CSPDirective][]CSPSource) *ContentSec
 	if policies == nil {
 		return EmptyContentSecurityPolicy()
 	}
-	return &ContentSecurityPolicy{policies, ""}
+	return &ContentSecurityPolicy{policies: policies}
 }
 
 // EmptyContentSecurityPolicy creates a blank slate Content-Security-Policy.
@@ -144,6 +156,18 @@ func EmptyContentSecurityPolicy() *ContentSecurityPolicy {
 	return NewContentSecurityPolicy(make(map[CSPDirective][]CSPSource))
 }
 
+// Clone returns a deep copy of the Content-Security-Policy, which can be changed without affecting the original.
+func (csp *ContentSecurityPolicy) Clone() *ContentSecurityPolicy {
+	policies := make(map[CSPDirective][]CSPSource, len(csp.policies))
+	for directive, sources := range csp.policies {
+		policies[directive] = append([]CSPSource{}, sources...)
+	}
+
+	clone := NewContentSecurityPolicy(policies)
+	clone.nonces = append([]CSPDirective{}, csp.nonces...)
+	return clone
+}
+
 // Add adds a directive and its sources.
 func (csp *ContentSecurityPolicy) Add(directive CSPDirective, sources ...CSPSource) {
 	if len(directive) == 0 {
@@ -213,12 +237,39 @@ func (csp *ContentSecurityPolicy) String() string {
 
 // Empty returns whether the Content-Security-Policy is empty.
 func (csp *ContentSecurityPolicy) Empty() bool {
-	return len(csp.policies) == 0
+	return len(csp.policies) == 0 && len(csp.nonces) == 0
+}
+
+// HeaderFor adds the Content-Security-Policy HTTP security header for the given http.Request to the given http.ResponseWriter.
+// A Content-Security-Policy that only has nonces renders empty without a request nonce, and isn't sent then.
+func (csp *ContentSecurityPolicy) HeaderFor(w http.ResponseWriter, r *http.Request) {
+	if csp.Empty() {
+		return
+	}
+
+	if policy := csp.stringFor(r); policy != "" {
+		w.Header().Set(HeaderContentSecurityPolicy, policy)
+	}
 }
 
 // Header adds the Content-Security-Policy HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
 func (csp *ContentSecurityPolicy) Header(w http.ResponseWriter) {
-	if !csp.Empty() {
-		w.Header().Set(HeaderContentSecurityPolicy, csp.String())
+	csp.HeaderFor(w, nil)
+}
+
+// cspReportOnly sends a Content-Security-Policy as the Content-Security-Policy-Report-Only HTTP security header,
+// which browsers only report violations of instead of enforcing.
+type cspReportOnly struct {
+	*ContentSecurityPolicy
+}
+
+func (csp cspReportOnly) HeaderFor(w http.ResponseWriter, r *http.Request) {
+	if csp.Empty() {
+		return
+	}
+
+	if policy := csp.stringFor(r); policy != "" {
+		w.Header().Set(HeaderContentSecurityPolicyReportOnly, policy)
 	}
 }
@@ -323,3 +323,23 @@ func TestCSP_Empty(t *testing.T) {
 		})
 	}
 }
+
+func TestContentSecurityPolicy_Clone(t *testing.T) {
+	t.Parallel()
+
+	csp := NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
+		DirectiveDefaultSrc: {SourceSelf},
+	})
+	clone := csp.Clone()
+
+	clone.Add(DirectiveDefaultSrc, "https://example.com")
+	clone.Add(DirectiveImgSrc, SourceData)
+
+	if header := csp.String(); header != "default-src 'self'" {
+		t.Errorf("Original should not change\tExpected: %s\tActual: %s\n", "default-src 'self'", header)
+	}
+
+	if len(clone.policies) != 2 || len(clone.policies[DirectiveDefaultSrc]) != 2 {
+		t.Errorf("Clone should change\tActual: %s\n", clone)
+	}
+}
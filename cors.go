@@ -0,0 +1,266 @@
+package helmet
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// List of all CORS HTTP headers.
+const (
+	HeaderOrigin                        = "Origin"
+	HeaderVary                          = "Vary"
+	HeaderAccessControlAllowOrigin      = "Access-Control-Allow-Origin"
+	HeaderAccessControlAllowCredentials = "Access-Control-Allow-Credentials"
+	HeaderAccessControlAllowMethods     = "Access-Control-Allow-Methods"
+	HeaderAccessControlAllowHeaders     = "Access-Control-Allow-Headers"
+	HeaderAccessControlExposeHeaders    = "Access-Control-Expose-Headers"
+	HeaderAccessControlMaxAge           = "Access-Control-Max-Age"
+	HeaderAccessControlRequestMethod    = "Access-Control-Request-Method"
+	HeaderAccessControlRequestHeaders   = "Access-Control-Request-Headers"
+)
+
+// ModuleCORS is the name of the CORS Module.
+const ModuleCORS = "CORS"
+
+// CORSOriginWildcard allows every origin.
+const CORSOriginWildcard = "*"
+
+// CORS represents Cross-Origin Resource Sharing, which lets other origins read this site's responses.
+type CORS struct {
+	// Origins that are allowed, either exactly ("https://example.com"), by subdomain wildcard ("https://*.example.com"),
+	// or all of them ("*").
+	AllowedOrigins []string
+
+	// Regular expressions that allowed origins must fully match, for anything AllowedOrigins can't express.
+	AllowedOriginPatterns []*regexp.Regexp
+
+	// Methods allowed in preflight requests. Defaults to GET, HEAD and POST.
+	AllowedMethods []string
+
+	// Request headers allowed in preflight requests. "*" allows whatever is requested.
+	AllowedHeaders []string
+
+	// Response headers that the other origins may read.
+	ExposedHeaders []string
+
+	// Whether requests may include credentials, such as cookies. Only origins that are allowed explicitly get credentials,
+	// origins that only the wildcard allows don't.
+	AllowCredentials bool
+
+	// The time, in seconds, that browsers may cache a preflight response for.
+	MaxAge int
+
+	anchoredPatterns sync.Map // AllowedOriginPatterns, anchored to match the whole origin
+}
+
+// NewCORS creates a new CORS that allows the given origins.
+func NewCORS(allowedOrigins ...string) *CORS {
+	return &CORS{AllowedOrigins: allowedOrigins}
+}
+
+// EmptyCORS creates a blank slate CORS.
+func EmptyCORS() *CORS {
+	return NewCORS()
+}
+
+// Name returns the name of the CORS Module.
+func (cors *CORS) Name() string {
+	return ModuleCORS
+}
+
+// Empty returns whether the CORS is empty.
+func (cors *CORS) Empty() bool {
+	return len(cors.AllowedOrigins) == 0 && len(cors.AllowedOriginPatterns) == 0
+}
+
+// AllowsCrossOrigin returns whether any origin other than this site's own is allowed.
+func (cors *CORS) AllowsCrossOrigin() bool {
+	return !cors.Empty()
+}
+
+// Header adds the CORS HTTP headers for the given http.Request's origin to the given http.ResponseWriter.
+// Credentials are only allowed for origins that are allowed explicitly, never for ones only the wildcard allows.
+func (cors *CORS) Header(w http.ResponseWriter, r *http.Request) {
+	if cors.Empty() {
+		return
+	}
+
+	// the response only differs per origin when the origin is reflected
+	if !cors.anyOrigin() {
+		w.Header().Add(HeaderVary, HeaderOrigin)
+	}
+
+	origin := r.Header.Get(HeaderOrigin)
+	if origin == "" || !cors.Allows(origin) {
+		return
+	}
+
+	if cors.anyOrigin() || !cors.allowsExplicitly(origin) {
+		w.Header().Set(HeaderAccessControlAllowOrigin, CORSOriginWildcard)
+	} else {
+		w.Header().Set(HeaderAccessControlAllowOrigin, origin)
+
+		if cors.AllowCredentials {
+			w.Header().Set(HeaderAccessControlAllowCredentials, "true")
+		}
+	}
+
+	if len(cors.ExposedHeaders) > 0 {
+		w.Header().Set(HeaderAccessControlExposeHeaders, strings.Join(cors.ExposedHeaders, ", "))
+	}
+}
+
+// Allow answers preflight requests itself with 204 No Content, and lets every other request continue.
+func (cors *CORS) Allow(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodOptions || r.Header.Get(HeaderAccessControlRequestMethod) == "" {
+		return true
+	}
+
+	w.Header().Add(HeaderVary, HeaderAccessControlRequestMethod)
+	w.Header().Add(HeaderVary, HeaderAccessControlRequestHeaders)
+
+	if w.Header().Get(HeaderAccessControlAllowOrigin) != "" {
+		w.Header().Set(HeaderAccessControlAllowMethods, strings.Join(cors.allowedMethods(), ", "))
+
+		if headers := cors.allowedHeaders(r); headers != "" {
+			w.Header().Set(HeaderAccessControlAllowHeaders, headers)
+		}
+
+		if cors.MaxAge > 0 {
+			w.Header().Set(HeaderAccessControlMaxAge, strconv.Itoa(cors.MaxAge))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return false
+}
+
+// answersRequests marks CORS as answering preflight requests, so that Helmet runs it after the other RequestFilters.
+func (cors *CORS) answersRequests() {}
+
+// Secure is the middleware handler, for using CORS without Helmet.
+func (cors *CORS) Secure(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cors.Header(w, r)
+		if cors.Allow(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// Allows returns whether the given origin is allowed.
+func (cors *CORS) Allows(origin string) bool {
+	return cors.wildcard() || cors.allowsExplicitly(origin)
+}
+
+// allowsExplicitly returns whether the given origin is allowed by anything but the wildcard.
+func (cors *CORS) allowsExplicitly(origin string) bool {
+	for _, allowedOrigin := range cors.AllowedOrigins {
+		if allowedOrigin != CORSOriginWildcard && strings.EqualFold(allowedOrigin, origin) {
+			return true
+		}
+
+		if strings.Contains(allowedOrigin, "://*.") && matchWildcardOrigin(allowedOrigin, origin) {
+			return true
+		}
+	}
+
+	for _, pattern := range cors.AllowedOriginPatterns {
+		if anchored := cors.anchoredPattern(pattern); anchored != nil && anchored.MatchString(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// anchoredPattern returns the given pattern anchored at both ends, or nil if it can't be, which never matches.
+// Checking where an unanchored match ends isn't enough, since with alternation the leftmost match can be a shorter one,
+// e.g. "https://a" for "https://a|https://a\.b". The anchors are added to the parsed pattern rather than to its source,
+// which could end in an unterminated \Q.
+func (cors *CORS) anchoredPattern(pattern *regexp.Regexp) *regexp.Regexp {
+	if anchored, ok := cors.anchoredPatterns.Load(pattern); ok {
+		return anchored.(*regexp.Regexp)
+	}
+
+	var anchored *regexp.Regexp
+	if re, err := syntax.Parse(pattern.String(), syntax.Perl); err == nil {
+		re = &syntax.Regexp{Op: syntax.OpConcat, Sub: []*syntax.Regexp{{Op: syntax.OpBeginText}, re, {Op: syntax.OpEndText}}}
+		if compiled, err := regexp.Compile(re.String()); err == nil {
+			anchored = compiled
+		}
+	}
+
+	cors.anchoredPatterns.Store(pattern, anchored)
+	return anchored
+}
+
+// anyOrigin returns whether every origin gets the same "*" response, which is the case when the wildcard is allowed,
+// unless credentials make the explicitly allowed origins get their origin reflected instead.
+func (cors *CORS) anyOrigin() bool {
+	if !cors.wildcard() {
+		return false
+	}
+
+	if !cors.AllowCredentials {
+		return true
+	}
+
+	for _, allowedOrigin := range cors.AllowedOrigins {
+		if allowedOrigin != CORSOriginWildcard {
+			return false
+		}
+	}
+	return len(cors.AllowedOriginPatterns) == 0
+}
+
+func (cors *CORS) wildcard() bool {
+	for _, allowedOrigin := range cors.AllowedOrigins {
+		if allowedOrigin == CORSOriginWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+func (cors *CORS) allowedMethods() []string {
+	if len(cors.AllowedMethods) == 0 {
+		return []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+	return cors.AllowedMethods
+}
+
+func (cors *CORS) allowedHeaders(r *http.Request) string {
+	for _, header := range cors.AllowedHeaders {
+		if header == "*" {
+			return r.Header.Get(HeaderAccessControlRequestHeaders)
+		}
+	}
+	return strings.Join(cors.AllowedHeaders, ", ")
+}
+
+// matchWildcardOrigin matches an origin like "https://api.example.com" against a pattern like "https://*.example.com".
+func matchWildcardOrigin(pattern string, origin string) bool {
+	patternURL, err := url.Parse(pattern)
+	if err != nil {
+		return false
+	}
+
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	if !strings.EqualFold(patternURL.Scheme, originURL.Scheme) || patternURL.Port() != originURL.Port() {
+		return false
+	}
+
+	suffix := strings.TrimPrefix(strings.ToLower(patternURL.Hostname()), "*")
+	host := strings.ToLower(originURL.Hostname())
+	return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+}
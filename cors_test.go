@@ -0,0 +1,280 @@
+package helmet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestCORS_Empty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		cors          *CORS
+		expectedEmpty bool
+	}{
+		{name: "Empty", cors: EmptyCORS(), expectedEmpty: true},
+		{name: "Origins", cors: NewCORS("https://example.com"), expectedEmpty: false},
+		{name: "Patterns", cors: &CORS{AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`https://.*`)}}, expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.cors.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
+
+func TestCORS_Allows(t *testing.T) {
+	t.Parallel()
+
+	cors := NewCORS("https://example.com", "https://*.example.org", "http://*.localhost:3000")
+	cors.AllowedOriginPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`https://pr-\d+\.preview\.dev`),
+		regexp.MustCompile(`https://a|https://a\.example\.net`),
+		regexp.MustCompile(`\Qhttps://quoted.example.com`),
+	}
+
+	testCases := []struct {
+		origin        string
+		expectedAllow bool
+	}{
+		{origin: "https://example.com", expectedAllow: true},
+		{origin: "https://EXAMPLE.com", expectedAllow: true},
+		{origin: "http://example.com", expectedAllow: false},
+		{origin: "https://example.com:8443", expectedAllow: false},
+		{origin: "https://evil-example.com", expectedAllow: false},
+		{origin: "https://api.example.org", expectedAllow: true},
+		{origin: "https://a.b.example.org", expectedAllow: true},
+		{origin: "https://example.org", expectedAllow: false},
+		{origin: "https://evilexample.org", expectedAllow: false},
+		{origin: "http://api.example.org", expectedAllow: false},
+		{origin: "http://app.localhost:3000", expectedAllow: true},
+		{origin: "http://app.localhost:4000", expectedAllow: false},
+		{origin: "https://pr-42.preview.dev", expectedAllow: true},
+		{origin: "https://pr-42.preview.dev.evil.com", expectedAllow: false},
+		{origin: "https://a", expectedAllow: true},
+		{origin: "https://a.example.net", expectedAllow: true},
+		{origin: "https://a.example.net.evil.com", expectedAllow: false},
+		{origin: "https://evil.com/https://a", expectedAllow: false},
+		{origin: "https://quoted.example.com", expectedAllow: true},
+		{origin: "https://quotedXexample.com", expectedAllow: false},
+		{origin: "https://quoted.example.com.evil.com", expectedAllow: false},
+		{origin: "null", expectedAllow: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.origin, func(t *testing.T) {
+			t.Parallel()
+
+			allow := cors.Allows(tc.origin)
+			if allow != tc.expectedAllow {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedAllow, allow)
+			}
+		})
+	}
+}
+
+func TestCORS_Secure(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		cors            *CORS
+		method          string
+		headers         map[string]string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedVary    []string
+	}{
+		{
+			name:            "Allowed Origin",
+			cors:            &CORS{AllowedOrigins: []string{"https://example.com"}, ExposedHeaders: []string{"X-Request-Id"}},
+			method:          http.MethodGet,
+			headers:         map[string]string{HeaderOrigin: "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: "https://example.com", HeaderAccessControlExposeHeaders: "X-Request-Id", HeaderAccessControlAllowCredentials: ""},
+			expectedVary:    []string{HeaderOrigin},
+		},
+		{
+			name:            "Disallowed Origin",
+			cors:            NewCORS("https://example.com"),
+			method:          http.MethodGet,
+			headers:         map[string]string{HeaderOrigin: "https://evil.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: ""},
+			expectedVary:    []string{HeaderOrigin},
+		},
+		{
+			name:            "No Origin",
+			cors:            NewCORS("https://example.com"),
+			method:          http.MethodGet,
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: ""},
+			expectedVary:    []string{HeaderOrigin},
+		},
+		{
+			name:            "Wildcard",
+			cors:            NewCORS(CORSOriginWildcard),
+			method:          http.MethodGet,
+			headers:         map[string]string{HeaderOrigin: "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: "*"},
+			expectedVary:    nil,
+		},
+		{
+			name:            "Wildcard, Credentials",
+			cors:            &CORS{AllowedOrigins: []string{CORSOriginWildcard}, AllowCredentials: true},
+			method:          http.MethodGet,
+			headers:         map[string]string{HeaderOrigin: "https://evil.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: "*", HeaderAccessControlAllowCredentials: ""},
+			expectedVary:    nil,
+		},
+		{
+			name:            "Wildcard, Credentials, Explicit Origin",
+			cors:            &CORS{AllowedOrigins: []string{CORSOriginWildcard, "https://example.com"}, AllowCredentials: true},
+			method:          http.MethodGet,
+			headers:         map[string]string{HeaderOrigin: "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: "https://example.com", HeaderAccessControlAllowCredentials: "true"},
+			expectedVary:    []string{HeaderOrigin},
+		},
+		{
+			name:            "Wildcard, Credentials, Other Origin",
+			cors:            &CORS{AllowedOrigins: []string{CORSOriginWildcard, "https://example.com"}, AllowCredentials: true},
+			method:          http.MethodGet,
+			headers:         map[string]string{HeaderOrigin: "https://evil.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: "*", HeaderAccessControlAllowCredentials: ""},
+			expectedVary:    []string{HeaderOrigin},
+		},
+		{
+			name:   "Preflight",
+			cors:   &CORS{AllowedOrigins: []string{"https://example.com"}, AllowedMethods: []string{http.MethodPut}, AllowedHeaders: []string{"Content-Type"}, MaxAge: 600},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				HeaderOrigin:                      "https://example.com",
+				HeaderAccessControlRequestMethod:  http.MethodPut,
+				HeaderAccessControlRequestHeaders: "content-type",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				HeaderAccessControlAllowOrigin:  "https://example.com",
+				HeaderAccessControlAllowMethods: http.MethodPut,
+				HeaderAccessControlAllowHeaders: "Content-Type",
+				HeaderAccessControlMaxAge:       "600",
+			},
+			expectedVary: []string{HeaderOrigin, HeaderAccessControlRequestMethod, HeaderAccessControlRequestHeaders},
+		},
+		{
+			name:   "Preflight, Reflected Headers, Default Methods",
+			cors:   &CORS{AllowedOrigins: []string{"https://example.com"}, AllowedHeaders: []string{"*"}},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				HeaderOrigin:                      "https://example.com",
+				HeaderAccessControlRequestMethod:  http.MethodPost,
+				HeaderAccessControlRequestHeaders: "x-custom",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				HeaderAccessControlAllowMethods: "GET, HEAD, POST",
+				HeaderAccessControlAllowHeaders: "x-custom",
+				HeaderAccessControlMaxAge:       "",
+			},
+			expectedVary: []string{HeaderOrigin, HeaderAccessControlRequestMethod, HeaderAccessControlRequestHeaders},
+		},
+		{
+			name:   "Preflight, Disallowed Origin",
+			cors:   NewCORS("https://example.com"),
+			method: http.MethodOptions,
+			headers: map[string]string{
+				HeaderOrigin:                     "https://evil.com",
+				HeaderAccessControlRequestMethod: http.MethodDelete,
+			},
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: "", HeaderAccessControlAllowMethods: ""},
+			expectedVary:    []string{HeaderOrigin, HeaderAccessControlRequestMethod, HeaderAccessControlRequestHeaders},
+		},
+		{
+			name:            "Plain OPTIONS",
+			cors:            NewCORS("https://example.com"),
+			method:          http.MethodOptions,
+			headers:         map[string]string{HeaderOrigin: "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{HeaderAccessControlAllowOrigin: "https://example.com"},
+			expectedVary:    []string{HeaderOrigin},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tc.method, "/", nil)
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+
+			rr := httptest.NewRecorder()
+			tc.cors.Secure(mockNext).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Incorrect status\tExpected: %d\tActual: %d\n", tc.expectedStatus, resp.StatusCode)
+			}
+
+			for name, expectedHeader := range tc.expectedHeaders {
+				if header := resp.Header.Get(name); header != expectedHeader {
+					t.Errorf("Incorrect %s\tExpected: %s\tActual: %s\n", name, expectedHeader, header)
+				}
+			}
+
+			vary := resp.Header.Values(HeaderVary)
+			if len(vary) != len(tc.expectedVary) {
+				t.Fatalf("Incorrect Vary\tExpected: %v\tActual: %v\n", tc.expectedVary, vary)
+			}
+			for i := range vary {
+				if vary[i] != tc.expectedVary[i] {
+					t.Errorf("Incorrect Vary\tExpected: %v\tActual: %v\n", tc.expectedVary, vary)
+				}
+			}
+		})
+	}
+}
+
+func TestHelmet_Secure_cors(t *testing.T) {
+	t.Parallel()
+
+	rr, r := newRecorderRequest(t)
+	r.Method = http.MethodOptions
+	r.Header.Set(HeaderOrigin, "https://example.com")
+	r.Header.Set(HeaderAccessControlRequestMethod, http.MethodPost)
+
+	helmet := Default()
+	helmet.CORS = NewCORS("https://example.com")
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+	resp := rr.Result()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Preflight should not reach the next handler\tExpected: %d\tActual: %d\n", http.StatusNoContent, resp.StatusCode)
+	}
+
+	if header := resp.Header.Get(HeaderAccessControlAllowOrigin); header != "https://example.com" {
+		t.Errorf("Expected: %s\tActual: %s\n", "https://example.com", header)
+	}
+
+	if header := resp.Header.Get(HeaderXFrameOptions); header != XFrameOptionsSameOrigin.String() {
+		t.Errorf("Preflight should still get the security headers\tExpected: %s\tActual: %s\n", XFrameOptionsSameOrigin, header)
+	}
+}
@@ -0,0 +1,38 @@
+package helmet
+
+import "net/http"
+
+// HeaderCrossOriginEmbedderPolicy is the Cross-Origin-Embedder-Policy HTTP security header.
+const HeaderCrossOriginEmbedderPolicy = "Cross-Origin-Embedder-Policy"
+
+// Cross-Origin-Embedder-Policy options.
+const (
+	CrossOriginEmbedderPolicyUnsafeNone     CrossOriginEmbedderPolicy = "unsafe-none"
+	CrossOriginEmbedderPolicyRequireCorp    CrossOriginEmbedderPolicy = "require-corp"
+	CrossOriginEmbedderPolicyCredentialless CrossOriginEmbedderPolicy = "credentialless"
+)
+
+// CrossOriginEmbedderPolicy represents the Cross-Origin-Embedder-Policy HTTP security header.
+type CrossOriginEmbedderPolicy string
+
+func (coep CrossOriginEmbedderPolicy) String() string {
+	return string(coep)
+}
+
+// Empty returns whether the Cross-Origin-Embedder-Policy is empty.
+func (coep CrossOriginEmbedderPolicy) Empty() bool {
+	return coep.String() == ""
+}
+
+// HeaderFor adds the Cross-Origin-Embedder-Policy HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (coep CrossOriginEmbedderPolicy) HeaderFor(w http.ResponseWriter, r *http.Request) {
+	if !coep.Empty() {
+		w.Header().Set(HeaderCrossOriginEmbedderPolicy, coep.String())
+	}
+}
+
+// Header adds the Cross-Origin-Embedder-Policy HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (coep CrossOriginEmbedderPolicy) Header(w http.ResponseWriter) {
+	coep.HeaderFor(w, nil)
+}
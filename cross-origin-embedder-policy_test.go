@@ -0,0 +1,57 @@
+package helmet
+
+import "testing"
+
+func TestCrossOriginEmbedderPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                      string
+		crossOriginEmbedderPolicy CrossOriginEmbedderPolicy
+		expectedHeader            string
+	}{
+		{name: "Empty", crossOriginEmbedderPolicy: "", expectedHeader: ""},
+		{name: "Unsafe None", crossOriginEmbedderPolicy: CrossOriginEmbedderPolicyUnsafeNone, expectedHeader: "unsafe-none"},
+		{name: "Require Corp", crossOriginEmbedderPolicy: CrossOriginEmbedderPolicyRequireCorp, expectedHeader: "require-corp"},
+		{name: "Credentialless", crossOriginEmbedderPolicy: CrossOriginEmbedderPolicyCredentialless, expectedHeader: "credentialless"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := tc.crossOriginEmbedderPolicy.String()
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+}
+
+func TestCrossOriginEmbedderPolicy_Empty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                      string
+		crossOriginEmbedderPolicy CrossOriginEmbedderPolicy
+		expectedEmpty             bool
+	}{
+		{name: "Empty", crossOriginEmbedderPolicy: "", expectedEmpty: true},
+		{name: "Unsafe None", crossOriginEmbedderPolicy: CrossOriginEmbedderPolicyUnsafeNone, expectedEmpty: false},
+		{name: "Require Corp", crossOriginEmbedderPolicy: CrossOriginEmbedderPolicyRequireCorp, expectedEmpty: false},
+		{name: "Credentialless", crossOriginEmbedderPolicy: CrossOriginEmbedderPolicyCredentialless, expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.crossOriginEmbedderPolicy.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
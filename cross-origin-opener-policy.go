@@ -0,0 +1,38 @@
+package helmet
+
+import "net/http"
+
+// HeaderCrossOriginOpenerPolicy is the Cross-Origin-Opener-Policy HTTP security header.
+const HeaderCrossOriginOpenerPolicy = "Cross-Origin-Opener-Policy"
+
+// Cross-Origin-Opener-Policy options.
+const (
+	CrossOriginOpenerPolicyUnsafeNone            CrossOriginOpenerPolicy = "unsafe-none"
+	CrossOriginOpenerPolicySameOriginAllowPopups CrossOriginOpenerPolicy = "same-origin-allow-popups"
+	CrossOriginOpenerPolicySameOrigin            CrossOriginOpenerPolicy = "same-origin"
+)
+
+// CrossOriginOpenerPolicy represents the Cross-Origin-Opener-Policy HTTP security header.
+type CrossOriginOpenerPolicy string
+
+func (coop CrossOriginOpenerPolicy) String() string {
+	return string(coop)
+}
+
+// Empty returns whether the Cross-Origin-Opener-Policy is empty.
+func (coop CrossOriginOpenerPolicy) Empty() bool {
+	return coop.String() == ""
+}
+
+// HeaderFor adds the Cross-Origin-Opener-Policy HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (coop CrossOriginOpenerPolicy) HeaderFor(w http.ResponseWriter, r *http.Request) {
+	if !coop.Empty() {
+		w.Header().Set(HeaderCrossOriginOpenerPolicy, coop.String())
+	}
+}
+
+// Header adds the Cross-Origin-Opener-Policy HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (coop CrossOriginOpenerPolicy) Header(w http.ResponseWriter) {
+	coop.HeaderFor(w, nil)
+}
@@ -0,0 +1,57 @@
+package helmet
+
+import "testing"
+
+func TestCrossOriginOpenerPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                    string
+		crossOriginOpenerPolicy CrossOriginOpenerPolicy
+		expectedHeader          string
+	}{
+		{name: "Empty", crossOriginOpenerPolicy: "", expectedHeader: ""},
+		{name: "Unsafe None", crossOriginOpenerPolicy: CrossOriginOpenerPolicyUnsafeNone, expectedHeader: "unsafe-none"},
+		{name: "Same Origin Allow Popups", crossOriginOpenerPolicy: CrossOriginOpenerPolicySameOriginAllowPopups, expectedHeader: "same-origin-allow-popups"},
+		{name: "Same Origin", crossOriginOpenerPolicy: CrossOriginOpenerPolicySameOrigin, expectedHeader: "same-origin"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := tc.crossOriginOpenerPolicy.String()
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+}
+
+func TestCrossOriginOpenerPolicy_Empty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                    string
+		crossOriginOpenerPolicy CrossOriginOpenerPolicy
+		expectedEmpty           bool
+	}{
+		{name: "Empty", crossOriginOpenerPolicy: "", expectedEmpty: true},
+		{name: "Unsafe None", crossOriginOpenerPolicy: CrossOriginOpenerPolicyUnsafeNone, expectedEmpty: false},
+		{name: "Same Origin Allow Popups", crossOriginOpenerPolicy: CrossOriginOpenerPolicySameOriginAllowPopups, expectedEmpty: false},
+		{name: "Same Origin", crossOriginOpenerPolicy: CrossOriginOpenerPolicySameOrigin, expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.crossOriginOpenerPolicy.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package helmet
+
+import "net/http"
+
+// HeaderCrossOriginResourcePolicy is the Cross-Origin-Resource-Policy HTTP security header.
+const HeaderCrossOriginResourcePolicy = "Cross-Origin-Resource-Policy"
+
+// Cross-Origin-Resource-Policy options.
+const (
+	CrossOriginResourcePolicySameSite    CrossOriginResourcePolicy = "same-site"
+	CrossOriginResourcePolicySameOrigin  CrossOriginResourcePolicy = "same-origin"
+	CrossOriginResourcePolicyCrossOrigin CrossOriginResourcePolicy = "cross-origin"
+)
+
+// CrossOriginResourcePolicy represents the Cross-Origin-Resource-Policy HTTP security header.
+type CrossOriginResourcePolicy string
+
+func (corp CrossOriginResourcePolicy) String() string {
+	return string(corp)
+}
+
+// Empty returns whether the Cross-Origin-Resource-Policy is empty.
+func (corp CrossOriginResourcePolicy) Empty() bool {
+	return corp.String() == ""
+}
+
+// HeaderFor adds the Cross-Origin-Resource-Policy HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (corp CrossOriginResourcePolicy) HeaderFor(w http.ResponseWriter, r *http.Request) {
+	if !corp.Empty() {
+		w.Header().Set(HeaderCrossOriginResourcePolicy, corp.String())
+	}
+}
+
+// Header adds the Cross-Origin-Resource-Policy HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (corp CrossOriginResourcePolicy) Header(w http.ResponseWriter) {
+	corp.HeaderFor(w, nil)
+}
@@ -0,0 +1,57 @@
+package helmet
+
+import "testing"
+
+func TestCrossOriginResourcePolicy_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                      string
+		crossOriginResourcePolicy CrossOriginResourcePolicy
+		expectedHeader            string
+	}{
+		{name: "Empty", crossOriginResourcePolicy: "", expectedHeader: ""},
+		{name: "Same Site", crossOriginResourcePolicy: CrossOriginResourcePolicySameSite, expectedHeader: "same-site"},
+		{name: "Same Origin", crossOriginResourcePolicy: CrossOriginResourcePolicySameOrigin, expectedHeader: "same-origin"},
+		{name: "Cross Origin", crossOriginResourcePolicy: CrossOriginResourcePolicyCrossOrigin, expectedHeader: "cross-origin"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := tc.crossOriginResourcePolicy.String()
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+}
+
+func TestCrossOriginResourcePolicy_Empty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                      string
+		crossOriginResourcePolicy CrossOriginResourcePolicy
+		expectedEmpty             bool
+	}{
+		{name: "Empty", crossOriginResourcePolicy: "", expectedEmpty: true},
+		{name: "Same Site", crossOriginResourcePolicy: CrossOriginResourcePolicySameSite, expectedEmpty: false},
+		{name: "Same Origin", crossOriginResourcePolicy: CrossOriginResourcePolicySameOrigin, expectedEmpty: false},
+		{name: "Cross Origin", crossOriginResourcePolicy: CrossOriginResourcePolicyCrossOrigin, expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.crossOriginResourcePolicy.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package helmet
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CSPChange represents how a change to a Content-Security-Policy affects security.
+type CSPChange int
+
+// List of all CSPChange values.
+const (
+	CSPUnchanged CSPChange = iota // nothing is allowed or blocked that wasn't before
+	CSPTightened                  // something that was allowed is blocked now
+	CSPLoosened                   // something that was blocked is allowed now
+	CSPMixed                      // both tightened and loosened
+)
+
+func (c CSPChange) String() string {
+	switch c {
+	case CSPTightened:
+		return "tightened"
+	case CSPLoosened:
+		return "loosened"
+	case CSPMixed:
+		return "tightened and loosened"
+	default:
+		return "unchanged"
+	}
+}
+
+// combine returns the CSPChange of both changes happening together.
+func (c CSPChange) combine(other CSPChange) CSPChange {
+	switch {
+	case c == other || other == CSPUnchanged:
+		return c
+	case c == CSPUnchanged:
+		return other
+	default:
+		return CSPMixed
+	}
+}
+
+// CSPEffectiveChange represents a change in the sources that a directive resolves to after fallback.
+type CSPEffectiveChange struct {
+	Directive CSPDirective
+	Before    CSPResolution
+	After     CSPResolution
+	Added     []CSPSource // effective sources that are new, empty if the directive became restricted or unrestricted
+	Removed   []CSPSource // effective sources that are gone, empty if the directive became restricted or unrestricted
+	Change    CSPChange
+}
+
+func (c CSPEffectiveChange) String() string {
+	return fmt.Sprintf("%s, was %s (%s)", c.After, c.Before, c.Change)
+}
+
+// CSPDiff represents the semantic differences between two Content-Security-Policies.
+type CSPDiff struct {
+	AddedDirectives   []CSPDirective
+	RemovedDirectives []CSPDirective
+	AddedSources      map[CSPDirective][]CSPSource // sources added to directives that are in both policies
+	RemovedSources    map[CSPDirective][]CSPSource // sources removed from directives that are in both policies
+	Effective         []CSPEffectiveChange         // directives whose effective sources changed, sorted
+}
+
+// ParseContentSecurityPolicy parses a Content-Security-Policy HTTP header value.
+// Like browsers do, directive names are case-insensitive and only the first of duplicate directives counts.
+func ParseContentSecurityPolicy(header string) *ContentSecurityPolicy {
+	csp := EmptyContentSecurityPolicy()
+
+	for _, policy := range strings.Split(header, ";") {
+		fields := strings.Fields(policy)
+		if len(fields) == 0 {
+			continue
+		}
+
+		directive := CSPDirective(strings.ToLower(fields[0]))
+		if _, ok := csp.policies[directive]; ok {
+			continue
+		}
+
+		csp.create(directive)
+		for _, source := range fields[1:] {
+			csp.policies[directive] = append(csp.policies[directive], CSPSource(source))
+		}
+	}
+
+	return csp
+}
+
+// DiffContentSecurityPolicies compares a Content-Security-Policy before and after a change.
+func DiffContentSecurityPolicies(before, after *ContentSecurityPolicy) CSPDiff {
+	if before == nil {
+		before = EmptyContentSecurityPolicy()
+	}
+	if after == nil {
+		after = EmptyContentSecurityPolicy()
+	}
+
+	diff := CSPDiff{
+		AddedDirectives:   []CSPDirective{},
+		RemovedDirectives: []CSPDirective{},
+		AddedSources:      map[CSPDirective][]CSPSource{},
+		RemovedSources:    map[CSPDirective][]CSPSource{},
+		Effective:         []CSPEffectiveChange{},
+	}
+
+	directives := map[CSPDirective]bool{}
+	for _, directive := range CSPFetchDirectives {
+		directives[directive] = true
+	}
+
+	for directive, afterSources := range after.policies {
+		directives[directive] = true
+
+		beforeSources, ok := before.policies[directive]
+		if !ok {
+			diff.AddedDirectives = append(diff.AddedDirectives, directive)
+			continue
+		}
+
+		if added := subtractCSPSources(afterSources, beforeSources); len(added) > 0 {
+			diff.AddedSources[directive] = added
+		}
+		if removed := subtractCSPSources(beforeSources, afterSources); len(removed) > 0 {
+			diff.RemovedSources[directive] = removed
+		}
+	}
+
+	for directive := range before.policies {
+		directives[directive] = true
+
+		if _, ok := after.policies[directive]; !ok {
+			diff.RemovedDirectives = append(diff.RemovedDirectives, directive)
+		}
+	}
+
+	sorted := []CSPDirective{}
+	for directive := range directives {
+		sorted = append(sorted, directive)
+	}
+	sortCSPDirectives(sorted)
+	sortCSPDirectives(diff.AddedDirectives)
+	sortCSPDirectives(diff.RemovedDirectives)
+
+	for _, directive := range sorted {
+		if change, ok := diffCSPResolutions(before.Resolve(directive), after.Resolve(directive)); ok {
+			diff.Effective = append(diff.Effective, change)
+		}
+	}
+
+	return diff
+}
+
+// DiffContentSecurityPolicyHeaders compares a Content-Security-Policy HTTP header value before and after a change.
+func DiffContentSecurityPolicyHeaders(before, after string) CSPDiff {
+	return DiffContentSecurityPolicies(ParseContentSecurityPolicy(before), ParseContentSecurityPolicy(after))
+}
+
+// Empty returns whether the Content-Security-Policies are the same.
+func (d CSPDiff) Empty() bool {
+	return len(d.AddedDirectives) == 0 && len(d.RemovedDirectives) == 0 &&
+		len(d.AddedSources) == 0 && len(d.RemovedSources) == 0 && len(d.Effective) == 0
+}
+
+// Change returns how the differences affect security overall.
+func (d CSPDiff) Change() CSPChange {
+	change := CSPUnchanged
+	for _, effective := range d.Effective {
+		change = change.combine(effective.Change)
+	}
+	return change
+}
+
+// String generates a reviewable report of the differences, one per line.
+func (d CSPDiff) String() string {
+	lines := []string{}
+
+	for _, directive := range d.AddedDirectives {
+		lines = append(lines, fmt.Sprintf("+ directive %s", directive))
+	}
+	for _, directive := range d.RemovedDirectives {
+		lines = append(lines, fmt.Sprintf("- directive %s", directive))
+	}
+
+	changed := []CSPDirective{}
+	for directive := range d.AddedSources {
+		changed = append(changed, directive)
+	}
+	for directive := range d.RemovedSources {
+		if _, ok := d.AddedSources[directive]; !ok {
+			changed = append(changed, directive)
+		}
+	}
+	sortCSPDirectives(changed)
+
+	for _, directive := range changed {
+		for _, source := range d.AddedSources[directive] {
+			lines = append(lines, fmt.Sprintf("+ %s %s", directive, source))
+		}
+		for _, source := range d.RemovedSources[directive] {
+			lines = append(lines, fmt.Sprintf("- %s %s", directive, source))
+		}
+	}
+
+	for _, effective := range d.Effective {
+		lines = append(lines, fmt.Sprintf("~ %s", effective))
+	}
+
+	lines = append(lines, fmt.Sprintf("= %s", d.Change()))
+	return strings.Join(lines, "\n")
+}
+
+// diffCSPResolutions compares what a directive resolves to before and after a change.
+// It returns false if the effective sources are the same.
+func diffCSPResolutions(before, after CSPResolution) (CSPEffectiveChange, bool) {
+	change := CSPEffectiveChange{Directive: after.Directive, Before: before, After: after, Added: []CSPSource{}, Removed: []CSPSource{}}
+
+	// reporting doesn't allow or block anything
+	if after.Directive == DirectiveReportTo || after.Directive == DeprecatedDirectiveReportURI {
+		return change, false
+	}
+
+	switch {
+	case !before.Restricted() && !after.Restricted():
+		return change, false
+	case !before.Restricted():
+		change.Change = CSPTightened
+		return change, true
+	case !after.Restricted():
+		change.Change = CSPLoosened
+		return change, true
+	}
+
+	beforeSources := effectiveCSPSources(before.Directive, before.Sources)
+	afterSources := effectiveCSPSources(after.Directive, after.Sources)
+	for _, source := range afterSources {
+		// 'strict-dynamic' only passes on the trust of nonces and hashes, it allows nothing by itself
+		if source != SourceStrictDynamic && !coversCSPSource(beforeSources, source) {
+			change.Added = append(change.Added, source)
+		}
+	}
+	for _, source := range beforeSources {
+		if source != SourceStrictDynamic && !coversCSPSource(afterSources, source) {
+			change.Removed = append(change.Removed, source)
+		}
+	}
+
+	if len(change.Added) > 0 {
+		change.Change = change.Change.combine(CSPLoosened)
+	}
+	if len(change.Removed) > 0 {
+		change.Change = change.Change.combine(CSPTightened)
+	}
+
+	return change, change.Change != CSPUnchanged
+}
+
+// effectiveCSPSources returns the normalized sources that allow something for the given directive, leaving out the ones
+// browsers ignore, the ones that don't allow anything by themselves, and the ones that other sources already cover.
+func effectiveCSPSources(directive CSPDirective, sources []CSPSource) []CSPSource {
+	allowing := []CSPSource{}
+	for _, source := range honouredCSPSources(directive, normalizeCSPSources(sources)) {
+		// 'none' only means the directive has no other sources, and 'report-sample' only changes reports
+		if source != SourceNone && source != SourceReportSample {
+			allowing = append(allowing, source)
+		}
+	}
+
+	effective := []CSPSource{}
+	for i, source := range allowing {
+		covered := false
+		for j, other := range allowing {
+			// of two sources that cover each other, the first one is kept
+			if i != j && coversCSPSource([]CSPSource{other}, source) && (j < i || !coversCSPSource([]CSPSource{source}, other)) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			effective = append(effective, source)
+		}
+	}
+	return effective
+}
+
+// coversCSPSource returns whether the sources allow everything the given source allows, whatever the document's origin,
+// e.g. https: covers https://a.example.com, and 'unsafe-inline' covers nonces and hashes.
+func coversCSPSource(sources []CSPSource, source CSPSource) bool {
+	source = normalizeCSPSource(source)
+
+	var u *url.URL
+	if s := string(source); strings.Contains(s, "://") || (strings.HasSuffix(s, ":") && !strings.HasPrefix(s, "'")) {
+		// a host or scheme source covers what matches the sources that match it, which wildcards also do: "*.example.com"
+		// only matches other hosts with that wildcard
+		if parsed, err := url.Parse(s); err == nil {
+			u = parsed
+		}
+	}
+
+	// the scheme of sources without one, and what 'self' matches, depends on the document
+	origins := []*url.URL{{Scheme: "http", Host: "origin.invalid"}, {Scheme: "https", Host: "origin.invalid"}}
+
+	for _, other := range sources {
+		other = normalizeCSPSource(other)
+
+		switch {
+		case other == source:
+			return true
+		case hasNonceOrHash([]CSPSource{source}):
+			if other == SourceUnsafeInline {
+				return true
+			}
+		case u != nil:
+			if matchCSPSource(other, u, origins[0]) && matchCSPSource(other, u, origins[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// subtractCSPSources returns the sources that are in a, but not in b, compared after normalization.
+func subtractCSPSources(a, b []CSPSource) []CSPSource {
+	exclude := map[CSPSource]bool{}
+	for _, source := range b {
+		exclude[normalizeCSPSource(source)] = true
+	}
+
+	result := []CSPSource{}
+	for _, source := range a {
+		if !exclude[normalizeCSPSource(source)] {
+			result = append(result, source)
+		}
+	}
+	return result
+}
+
+func sortCSPDirectives(directives []CSPDirective) {
+	sort.Slice(directives, func(i, j int) bool { return directives[i] < directives[j] })
+}
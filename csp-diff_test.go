@@ -0,0 +1,244 @@
+package helmet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseContentSecurityPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		header           string
+		expectedPolicies map[CSPDirective][]CSPSource
+	}{
+		{name: "Empty", header: "", expectedPolicies: map[CSPDirective][]CSPSource{}},
+		{
+			name:   "Directives",
+			header: "default-src 'self';  SCRIPT-SRC 'self'   https://cdn.com ; upgrade-insecure-requests;",
+			expectedPolicies: map[CSPDirective][]CSPSource{
+				DirectiveDefaultSrc:              {SourceSelf},
+				DirectiveScriptSrc:               {SourceSelf, "https://cdn.com"},
+				DirectiveUpgradeInsecureRequests: {},
+			},
+		},
+		{
+			name:             "Duplicate Directive",
+			header:           "img-src 'self'; img-src *",
+			expectedPolicies: map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceSelf}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csp := ParseContentSecurityPolicy(tc.header)
+			if !reflect.DeepEqual(csp.policies, tc.expectedPolicies) {
+				t.Errorf("Expected: %v\tActual: %v\n", tc.expectedPolicies, csp.policies)
+			}
+		})
+	}
+}
+
+func TestDiffContentSecurityPolicyHeaders(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		before            string
+		after             string
+		expectedEmpty     bool
+		expectedChange    CSPChange
+		expectedEffective []CSPDirective
+		expectedLines     []string // substrings of String()
+	}{
+		{
+			name:           "Same",
+			before:         "default-src 'self'; img-src 'self' data:",
+			after:          "img-src DATA: 'SELF'; default-src 'self'",
+			expectedEmpty:  true,
+			expectedChange: CSPUnchanged,
+		},
+		{
+			name:              "Added Source",
+			before:            "default-src 'self'; img-src 'self'",
+			after:             "default-src 'self'; img-src 'self' https://cdn.com",
+			expectedChange:    CSPLoosened,
+			expectedEffective: []CSPDirective{DirectiveImgSrc},
+			expectedLines:     []string{"+ img-src https://cdn.com", "= loosened"},
+		},
+		{
+			name:              "Removed Source",
+			before:            "connect-src 'self' https://api.com",
+			after:             "connect-src 'self'",
+			expectedChange:    CSPTightened,
+			expectedEffective: []CSPDirective{DirectiveConnectSrc},
+			expectedLines:     []string{"- connect-src https://api.com", "= tightened"},
+		},
+		{
+			name:              "Added Directive Loosens Fallback",
+			before:            "default-src 'self'",
+			after:             "default-src 'self'; style-src 'self' 'unsafe-inline'",
+			expectedChange:    CSPLoosened,
+			expectedEffective: []CSPDirective{DirectiveStyleSrc, DirectiveStyleSrcAttr, DirectiveStyleSrcElem},
+			expectedLines:     []string{"+ directive style-src", "~ style-src-elem -> style-src: 'self' 'unsafe-inline', was style-src-elem -> style-src -> default-src: 'self' (loosened)"},
+		},
+		{
+			name:              "Removed Directive",
+			before:            "frame-ancestors 'none'",
+			after:             "",
+			expectedChange:    CSPLoosened,
+			expectedEffective: []CSPDirective{DirectiveFrameAncestors},
+			expectedLines:     []string{"- directive frame-ancestors", "~ frame-ancestors: unrestricted, was frame-ancestors: 'none' (loosened)"},
+		},
+		{
+			name:              "None Replaced By Nothing",
+			before:            "object-src 'none'",
+			after:             "object-src",
+			expectedChange:    CSPUnchanged,
+			expectedEffective: []CSPDirective{},
+			expectedLines:     []string{"- object-src 'none'", "= unchanged"},
+		},
+		{
+			name:              "Reporting Only",
+			before:            "default-src 'self'; report-uri /a",
+			after:             "default-src 'self'; report-uri /b",
+			expectedChange:    CSPUnchanged,
+			expectedEffective: []CSPDirective{},
+			expectedLines:     []string{"+ report-uri /b", "- report-uri /a"},
+		},
+		{
+			name:              "Nonce Disables Unsafe Inline",
+			before:            "script-src 'self' 'unsafe-inline'",
+			after:             "script-src 'self' 'unsafe-inline' 'nonce-abc'",
+			expectedChange:    CSPTightened,
+			expectedEffective: []CSPDirective{DirectiveScriptSrc, DirectiveScriptSrcAttr, DirectiveScriptSrcElem, DirectiveWorkerSrc},
+			expectedLines:     []string{"+ script-src 'nonce-abc'", "= tightened"},
+		},
+		{
+			name:              "Hash Disables Unsafe Inline",
+			before:            "style-src 'unsafe-inline'",
+			after:             "style-src 'unsafe-inline' 'sha256-abc='",
+			expectedChange:    CSPTightened,
+			expectedEffective: []CSPDirective{DirectiveStyleSrc, DirectiveStyleSrcAttr, DirectiveStyleSrcElem},
+			expectedLines:     []string{"= tightened"},
+		},
+		{
+			name:              "Nonce Without Unsafe Inline",
+			before:            "script-src 'self'",
+			after:             "script-src 'self' 'nonce-abc'",
+			expectedChange:    CSPLoosened,
+			expectedEffective: []CSPDirective{DirectiveScriptSrc, DirectiveScriptSrcAttr, DirectiveScriptSrcElem, DirectiveWorkerSrc},
+			expectedLines:     []string{"= loosened"},
+		},
+		{
+			name:              "Strict Dynamic Disables Host Sources",
+			before:            "script-src 'nonce-abc' https://cdn.com 'unsafe-inline'",
+			after:             "script-src 'nonce-abc' 'strict-dynamic' https://cdn.com 'unsafe-inline'",
+			expectedChange:    CSPTightened,
+			expectedEffective: []CSPDirective{DirectiveScriptSrc, DirectiveScriptSrcAttr, DirectiveScriptSrcElem, DirectiveWorkerSrc},
+			expectedLines:     []string{"+ script-src 'strict-dynamic'", "= tightened"},
+		},
+		{
+			name:              "Strict Dynamic Outside Scripts",
+			before:            "style-src https://cdn.com",
+			after:             "style-src https://cdn.com 'strict-dynamic'",
+			expectedChange:    CSPUnchanged,
+			expectedEffective: []CSPDirective{},
+			expectedLines:     []string{"+ style-src 'strict-dynamic'", "= unchanged"},
+		},
+		{
+			name:              "Report Sample",
+			before:            "script-src 'self'",
+			after:             "script-src 'self' 'report-sample'",
+			expectedChange:    CSPUnchanged,
+			expectedEffective: []CSPDirective{},
+			expectedLines:     []string{"+ script-src 'report-sample'", "= unchanged"},
+		},
+		{
+			name:              "Covered Source",
+			before:            "img-src https:",
+			after:             "img-src https: https://a.example.com https://*.example.net/img/",
+			expectedChange:    CSPUnchanged,
+			expectedEffective: []CSPDirective{},
+			expectedLines:     []string{"+ img-src https://a.example.com", "= unchanged"},
+		},
+		{
+			name:              "Covered Source Removed",
+			before:            "img-src https://*.example.com https://a.example.com",
+			after:             "img-src https://*.example.com",
+			expectedChange:    CSPUnchanged,
+			expectedEffective: []CSPDirective{},
+			expectedLines:     []string{"- img-src https://a.example.com", "= unchanged"},
+		},
+		{
+			name:              "Narrowed To Covered Source",
+			before:            "img-src https:",
+			after:             "img-src https://a.example.com",
+			expectedChange:    CSPTightened,
+			expectedEffective: []CSPDirective{DirectiveImgSrc},
+			expectedLines:     []string{"= tightened"},
+		},
+		{
+			name:              "Widened To Wildcard Host",
+			before:            "img-src https://a.example.com",
+			after:             "img-src https://*.example.com",
+			expectedChange:    CSPLoosened,
+			expectedEffective: []CSPDirective{DirectiveImgSrc},
+			expectedLines:     []string{"~ img-src: https://*.example.com, was img-src: https://a.example.com (loosened)"},
+		},
+		{
+			name:              "Mixed",
+			before:            "script-src 'self'; img-src *",
+			after:             "script-src 'self' 'unsafe-eval'; img-src 'self'",
+			expectedChange:    CSPMixed,
+			expectedEffective: []CSPDirective{DirectiveImgSrc, DirectiveScriptSrc, DirectiveScriptSrcAttr, DirectiveScriptSrcElem, DirectiveWorkerSrc},
+			expectedLines:     []string{"= tightened and loosened"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			diff := DiffContentSecurityPolicyHeaders(tc.before, tc.after)
+
+			if diff.Empty() != tc.expectedEmpty {
+				t.Errorf("Incorrect Empty\tExpected: %t\tActual: %t\n%s\n", tc.expectedEmpty, diff.Empty(), diff)
+			}
+
+			if diff.Change() != tc.expectedChange {
+				t.Errorf("Incorrect Change\tExpected: %s\tActual: %s\n", tc.expectedChange, diff.Change())
+			}
+
+			effective := []CSPDirective{}
+			for _, change := range diff.Effective {
+				effective = append(effective, change.Directive)
+			}
+			if len(effective) != len(tc.expectedEffective) || (len(effective) > 0 && !reflect.DeepEqual(effective, tc.expectedEffective)) {
+				t.Errorf("Incorrect Effective\tExpected: %v\tActual: %v\n", tc.expectedEffective, effective)
+			}
+
+			str := diff.String()
+			for _, line := range tc.expectedLines {
+				if !strings.Contains(str, line) {
+					t.Errorf("Missing line\tExpected: %s\tActual:\n%s\n", line, str)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffContentSecurityPolicies_nil(t *testing.T) {
+	t.Parallel()
+
+	diff := DiffContentSecurityPolicies(nil, NewContentSecurityPolicy(map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}}))
+	if diff.Change() != CSPTightened {
+		t.Errorf("Expected: %s\tActual: %s\n", CSPTightened, diff.Change())
+	}
+}
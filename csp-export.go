@@ -0,0 +1,68 @@
+package helmet
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GoString returns the Go code that creates the Content-Security-Policy, with its directives sorted,
+// so that a generated policy can be pasted into an application. It doesn't include nonces.
+func (csp *ContentSecurityPolicy) GoString() string {
+	if len(csp.policies) == 0 {
+		return "helmet.EmptyContentSecurityPolicy()"
+	}
+
+	directives := make([]CSPDirective, 0, len(csp.policies))
+	width := 0
+	for directive := range csp.policies {
+		directives = append(directives, directive)
+		if len(strconv.Quote(string(directive))) > width {
+			width = len(strconv.Quote(string(directive)))
+		}
+	}
+	sortCSPDirectives(directives)
+
+	var b strings.Builder
+	b.WriteString("helmet.NewContentSecurityPolicy(map[helmet.CSPDirective][]helmet.CSPSource{\n")
+	for _, directive := range directives {
+		sources := []string{}
+		for _, source := range csp.policies[directive] {
+			sources = append(sources, strconv.Quote(string(source)))
+		}
+
+		// aligned the way gofmt aligns composite literals
+		key := strconv.Quote(string(directive)) + ":"
+		fmt.Fprintf(&b, "\t%-*s {%s},\n", width+1, key, strings.Join(sources, ", "))
+	}
+	b.WriteString("})")
+	return b.String()
+}
+
+// MarshalJSON encodes the Content-Security-Policy as an object of directives and their sources. It doesn't include nonces.
+func (csp *ContentSecurityPolicy) MarshalJSON() ([]byte, error) {
+	policies := make(map[CSPDirective][]CSPSource, len(csp.policies))
+	for directive, sources := range csp.policies {
+		policies[directive] = append([]CSPSource{}, sources...)
+	}
+	return json.Marshal(policies)
+}
+
+// UnmarshalJSON decodes a Content-Security-Policy encoded by MarshalJSON, replacing its directives.
+func (csp *ContentSecurityPolicy) UnmarshalJSON(data []byte) error {
+	policies := map[CSPDirective][]CSPSource{}
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return err
+	}
+
+	for directive, sources := range policies {
+		if sources == nil {
+			policies[directive] = []CSPSource{}
+		}
+	}
+
+	csp.policies = policies
+	csp.cache = ""
+	return nil
+}
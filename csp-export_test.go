@@ -0,0 +1,84 @@
+package helmet
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestContentSecurityPolicy_GoString(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		csp      *ContentSecurityPolicy
+		expected string
+	}{
+		{name: "Empty", csp: EmptyContentSecurityPolicy(), expected: "helmet.EmptyContentSecurityPolicy()"},
+		{
+			name: "Directives",
+			csp: NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
+				DirectiveScriptSrc:               {SourceSelf, "https://cdn.example.com"},
+				DirectiveDefaultSrc:              {SourceNone},
+				DirectiveUpgradeInsecureRequests: {},
+			}),
+			expected: "helmet.NewContentSecurityPolicy(map[helmet.CSPDirective][]helmet.CSPSource{\n" +
+				"\t\"default-src\":               {\"'none'\"},\n" +
+				"\t\"script-src\":                {\"'self'\", \"https://cdn.example.com\"},\n" +
+				"\t\"upgrade-insecure-requests\": {},\n" +
+				"})",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			goString := fmt.Sprintf("%#v", tc.csp)
+			if goString != tc.expected {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expected, goString)
+			}
+		})
+	}
+}
+
+func TestContentSecurityPolicy_JSON(t *testing.T) {
+	t.Parallel()
+
+	csp := NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
+		DirectiveScriptSrc:               {SourceSelf, "https://cdn.example.com"},
+		DirectiveDefaultSrc:              {SourceNone},
+		DirectiveUpgradeInsecureRequests: nil,
+	})
+	csp.AddNonce(DirectiveStyleSrc)
+
+	data, err := json.Marshal(csp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	expected := `{"default-src":["'none'"],"script-src":["'self'","https://cdn.example.com"],"upgrade-insecure-requests":[]}`
+	if string(data) != expected {
+		t.Errorf("Expected: %s\tActual: %s\n", expected, data)
+	}
+
+	decoded := EmptyContentSecurityPolicy()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	expectedPolicies := map[CSPDirective][]CSPSource{
+		DirectiveScriptSrc:               {SourceSelf, "https://cdn.example.com"},
+		DirectiveDefaultSrc:              {SourceNone},
+		DirectiveUpgradeInsecureRequests: {},
+	}
+	if !reflect.DeepEqual(decoded.policies, expectedPolicies) {
+		t.Errorf("Expected: %v\tActual: %v\n", expectedPolicies, decoded.policies)
+	}
+
+	if err := json.Unmarshal([]byte(`["default-src"]`), decoded); err == nil {
+		t.Errorf("Invalid JSON should return an error\n")
+	}
+}
@@ -0,0 +1,318 @@
+package helmet
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxCSPReportSize caps how much of a violation report request body is read.
+const maxCSPReportSize = 64 << 10
+
+// cspLearnedDirectives are the directives a CSPLearner learns, besides the fetch directives. frame-ancestors can't be
+// learned: its reports block the framed document itself, never the ancestor that framed it.
+var cspLearnedDirectives = []CSPDirective{DirectiveBaseURI, DirectiveFormAction}
+
+// CSPLearningPolicy creates a Content-Security-Policy for learning which sources an application needs:
+// it restricts every directive a CSPLearner learns to 'none', and reports violations to the given URI.
+// Send it as the Content-Security-Policy-Report-Only, where it blocks nothing, but reports every resource.
+func CSPLearningPolicy(reportURI string) *ContentSecurityPolicy {
+	csp := EmptyContentSecurityPolicy()
+	csp.Add(DirectiveDefaultSrc, SourceNone)
+	for _, directive := range cspLearnedDirectives {
+		csp.Add(directive, SourceNone)
+	}
+	csp.Add(DeprecatedDirectiveReportURI, CSPSource(reportURI))
+	return csp
+}
+
+// CSPViolation represents a Content-Security-Policy violation report.
+type CSPViolation struct {
+	DocumentURL        string       // the URL of the document the violation happened in
+	EffectiveDirective CSPDirective // the directive that was violated, after falling back
+	BlockedURL         string       // the URL of the blocked resource, or a keyword such as "inline" or "eval"
+}
+
+// CSPObservation represents how often a source was needed by a directive, according to violation reports.
+type CSPObservation struct {
+	Directive CSPDirective
+	Source    CSPSource
+	Count     int
+}
+
+// CSPLearner proposes a Content-Security-Policy from the violation reports of the CSPLearningPolicy.
+// It is an http.Handler that accepts both report-uri reports and Reporting API reports, and aggregates them
+// by directive and blocked origin. Its proposal allows exactly what was reported, so review it before enforcing it.
+// It doesn't propose frame-ancestors, which has to be set from the sites that are known to frame the application.
+type CSPLearner struct {
+	// Distinct hosts of one scheme that a directive may allow before they are collapsed into a scheme source,
+	// e.g. https:. Zero never collapses.
+	MaxHostsPerScheme int
+
+	mu           sync.Mutex
+	observations map[CSPDirective]map[CSPSource]int
+}
+
+// NewCSPLearner creates a new CSPLearner, which collapses more than 10 hosts of one scheme into a scheme source.
+func NewCSPLearner() *CSPLearner {
+	return &CSPLearner{MaxHostsPerScheme: 10}
+}
+
+// ServeHTTP accepts POSTed violation reports, either application/csp-report or application/reports+json.
+func (l *CSPLearner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(HeaderContentType))
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCSPReportSize))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var violations []CSPViolation
+	switch mediaType {
+	case "application/csp-report", "application/json":
+		violations, err = parseCSPReport(body)
+	case "application/reports+json":
+		violations, err = parseReportingAPIReports(body)
+	default:
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	for _, violation := range violations {
+		l.Observe(violation)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseCSPReport parses a report sent to a report-uri, falling back to the violated directive for browsers
+// that don't send the effective one.
+func parseCSPReport(body []byte) ([]CSPViolation, error) {
+	var report struct {
+		CSPReport struct {
+			DocumentURI        string `json:"document-uri"`
+			EffectiveDirective string `json:"effective-directive"`
+			ViolatedDirective  string `json:"violated-directive"`
+			BlockedURI         string `json:"blocked-uri"`
+		} `json:"csp-report"`
+	}
+	if err := json.Unmarshal(body, &report); err != nil {
+		return nil, err
+	}
+
+	directive := report.CSPReport.EffectiveDirective
+	if directive == "" {
+		// older browsers send the directive along with its sources, e.g. "script-src 'self'"
+		directive, _, _ = strings.Cut(report.CSPReport.ViolatedDirective, " ")
+	}
+
+	return []CSPViolation{{
+		DocumentURL:        report.CSPReport.DocumentURI,
+		EffectiveDirective: CSPDirective(directive),
+		BlockedURL:         report.CSPReport.BlockedURI,
+	}}, nil
+}
+
+// parseReportingAPIReports parses the csp-violation reports of a batch sent by the Reporting API.
+func parseReportingAPIReports(body []byte) ([]CSPViolation, error) {
+	var reports []struct {
+		Type string `json:"type"`
+		Body struct {
+			DocumentURL        string `json:"documentURL"`
+			EffectiveDirective string `json:"effectiveDirective"`
+			BlockedURL         string `json:"blockedURL"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal(body, &reports); err != nil {
+		return nil, err
+	}
+
+	violations := []CSPViolation{}
+	for _, report := range reports {
+		if report.Type != "csp-violation" {
+			continue
+		}
+
+		violations = append(violations, CSPViolation{
+			DocumentURL:        report.Body.DocumentURL,
+			EffectiveDirective: CSPDirective(report.Body.EffectiveDirective),
+			BlockedURL:         report.Body.BlockedURL,
+		})
+	}
+	return violations, nil
+}
+
+// Observe records a violation, for reports that arrive some other way than through ServeHTTP.
+// Violations of directives the CSPLearner doesn't learn, or of unrecognized resources, are ignored.
+func (l *CSPLearner) Observe(violation CSPViolation) {
+	directive, ok := learnedCSPDirective(violation.EffectiveDirective)
+	if !ok {
+		return
+	}
+
+	source, ok := learnedCSPSource(violation)
+	if !ok {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.observations == nil {
+		l.observations = map[CSPDirective]map[CSPSource]int{}
+	}
+	if l.observations[directive] == nil {
+		l.observations[directive] = map[CSPSource]int{}
+	}
+	l.observations[directive][source]++
+}
+
+// learnedCSPDirective returns the directive a violation is learned for: the -elem and -attr directives are folded
+// into the directives they fall back to, since proposing them separately rarely pays off.
+func learnedCSPDirective(directive CSPDirective) (CSPDirective, bool) {
+	directive = CSPDirective(strings.ToLower(string(directive)))
+
+	switch directive {
+	case DirectiveScriptSrcElem, DirectiveScriptSrcAttr:
+		return DirectiveScriptSrc, true
+	case DirectiveStyleSrcElem, DirectiveStyleSrcAttr:
+		return DirectiveStyleSrc, true
+	}
+
+	if containsCSPDirective(CSPFetchDirectives, directive) || containsCSPDirective(cspLearnedDirectives, directive) {
+		return directive, true
+	}
+	return "", false
+}
+
+// learnedCSPSource returns the source that would have allowed the violation, at the granularity of origins:
+// 'self' for the document's own origin, a host source for other network origins, and a scheme source otherwise.
+func learnedCSPSource(violation CSPViolation) (CSPSource, bool) {
+	blocked := strings.ToLower(strings.TrimSpace(violation.BlockedURL))
+
+	switch blocked {
+	case "inline":
+		return SourceUnsafeInline, true
+	case "eval":
+		return SourceUnsafeEval, true
+	case "wasm-eval":
+		return SourceWasmUnsafeEval, true
+	case "self":
+		return SourceSelf, true
+	case "data", "blob", "filesystem", "mediastream":
+		// reports only contain the scheme of these URLs
+		return CSPSource(blocked + ":"), true
+	}
+
+	u, err := url.Parse(blocked)
+	if err != nil || u.Scheme == "" {
+		return "", false
+	}
+
+	if defaultPort(u.Scheme) == "" {
+		return CSPSource(u.Scheme + ":"), true
+	}
+	if u.Host == "" {
+		return "", false
+	}
+
+	if origin, err := url.Parse(violation.DocumentURL); err == nil && origin.Host != "" &&
+		matchCSPSource(SourceSelf, u, origin) {
+		return SourceSelf, true
+	}
+
+	host := u.Hostname()
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" && port != defaultPort(u.Scheme) {
+		host += ":" + port
+	}
+	return CSPSource(u.Scheme + "://" + host), true
+}
+
+// Observations returns every source that was needed, by directive, with the most frequent sources first.
+func (l *CSPLearner) Observations() []CSPObservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	observations := []CSPObservation{}
+	for directive, sources := range l.observations {
+		for source, count := range sources {
+			observations = append(observations, CSPObservation{Directive: directive, Source: source, Count: count})
+		}
+	}
+
+	sort.Slice(observations, func(i, j int) bool {
+		a, b := observations[i], observations[j]
+		if a.Directive != b.Directive {
+			return a.Directive < b.Directive
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Source < b.Source
+	})
+	return observations
+}
+
+// Propose returns a Content-Security-Policy that allows every observed source, and nothing else:
+// directives without observations are restricted to 'none', like in the CSPLearningPolicy.
+// Export it with %#v as Go code, or with encoding/json as JSON.
+func (l *CSPLearner) Propose() *ContentSecurityPolicy {
+	csp := CSPLearningPolicy("")
+	csp.Remove(DeprecatedDirectiveReportURI)
+
+	sources := map[CSPDirective][]CSPSource{}
+	for _, observation := range l.Observations() {
+		sources[observation.Directive] = append(sources[observation.Directive], observation.Source)
+	}
+
+	for directive, directiveSources := range sources {
+		directiveSources = collapseCSPHostSources(directiveSources, l.MaxHostsPerScheme)
+		sort.Slice(directiveSources, func(i, j int) bool { return directiveSources[i] < directiveSources[j] })
+
+		csp.Add(directive, directiveSources...)
+		csp.policies[directive] = normalizeCSPSources(csp.policies[directive])
+	}
+	return csp
+}
+
+// collapseCSPHostSources replaces the host sources of every scheme with more than limit hosts by its scheme source.
+func collapseCSPHostSources(sources []CSPSource, limit int) []CSPSource {
+	if limit <= 0 {
+		return sources
+	}
+
+	hosts := map[string]int{}
+	for _, source := range sources {
+		if scheme, _, ok := strings.Cut(string(source), "://"); ok {
+			hosts[scheme]++
+		}
+	}
+
+	collapsed := []CSPSource{}
+	for _, source := range sources {
+		if scheme, _, ok := strings.Cut(string(source), "://"); ok && hosts[scheme] > limit {
+			source = CSPSource(scheme + ":")
+		}
+		if !hasCSPSource(collapsed, source) {
+			collapsed = append(collapsed, source)
+		}
+	}
+	return collapsed
+}
@@ -0,0 +1,243 @@
+package helmet
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSPLearningPolicy(t *testing.T) {
+	t.Parallel()
+
+	csp := CSPLearningPolicy("/csp-reports")
+
+	expected := map[CSPDirective][]CSPSource{
+		DirectiveDefaultSrc:          {SourceNone},
+		DirectiveBaseURI:             {SourceNone},
+		DirectiveFormAction:          {SourceNone},
+		DeprecatedDirectiveReportURI: {"/csp-reports"},
+	}
+	if !reflect.DeepEqual(csp.policies, expected) {
+		t.Errorf("Expected: %v\tActual: %v\n", expected, csp.policies)
+	}
+}
+
+func TestCSPLearner_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                 string
+		method               string
+		contentType          string
+		body                 string
+		expectedStatusCode   int
+		expectedObservations []CSPObservation
+	}{
+		{
+			name:               "report-uri",
+			method:             http.MethodPost,
+			contentType:        "application/csp-report",
+			body:               `{"csp-report": {"document-uri": "https://example.com/", "effective-directive": "script-src-elem", "violated-directive": "default-src", "blocked-uri": "https://cdn.example.com/app.js"}}`,
+			expectedStatusCode: http.StatusNoContent,
+			expectedObservations: []CSPObservation{
+				{Directive: DirectiveScriptSrc, Source: "https://cdn.example.com", Count: 1},
+			},
+		},
+		{
+			name:               "report-uri, Violated Directive Only",
+			method:             http.MethodPost,
+			contentType:        "application/json",
+			body:               `{"csp-report": {"document-uri": "https://example.com/", "violated-directive": "img-src 'none'", "blocked-uri": "data"}}`,
+			expectedStatusCode: http.StatusNoContent,
+			expectedObservations: []CSPObservation{
+				{Directive: DirectiveImgSrc, Source: SourceData, Count: 1},
+			},
+		},
+		{
+			name:        "Reporting API",
+			method:      http.MethodPost,
+			contentType: "application/reports+json",
+			body: `[
+				{"type": "csp-violation", "url": "https://example.com/", "body": {"documentURL": "https://example.com/", "effectiveDirective": "style-src-elem", "blockedURL": "inline", "disposition": "report"}},
+				{"type": "deprecation", "url": "https://example.com/", "body": {"id": "x"}},
+				{"type": "csp-violation", "url": "https://example.com/", "body": {"documentURL": "https://example.com/", "effectiveDirective": "style-src-attr", "blockedURL": "inline", "disposition": "report"}}
+			]`,
+			expectedStatusCode: http.StatusNoContent,
+			expectedObservations: []CSPObservation{
+				{Directive: DirectiveStyleSrc, Source: SourceUnsafeInline, Count: 2},
+			},
+		},
+		{
+			name:                 "Not POST",
+			method:               http.MethodGet,
+			expectedStatusCode:   http.StatusMethodNotAllowed,
+			expectedObservations: []CSPObservation{},
+		},
+		{
+			name:                 "Unsupported Content-Type",
+			method:               http.MethodPost,
+			contentType:          "text/plain",
+			body:                 "hello",
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedObservations: []CSPObservation{},
+		},
+		{
+			name:                 "Invalid JSON",
+			method:               http.MethodPost,
+			contentType:          "application/csp-report",
+			body:                 `{"csp-report": `,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedObservations: []CSPObservation{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			learner := NewCSPLearner()
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, "/csp-reports", strings.NewReader(tc.body))
+			r.Header.Set(HeaderContentType, tc.contentType)
+			learner.ServeHTTP(rr, r)
+
+			if rr.Code != tc.expectedStatusCode {
+				t.Errorf("Incorrect status code\tExpected: %d\tActual: %d\n", tc.expectedStatusCode, rr.Code)
+			}
+
+			observations := learner.Observations()
+			if !reflect.DeepEqual(observations, tc.expectedObservations) {
+				t.Errorf("Incorrect observations\tExpected: %v\tActual: %v\n", tc.expectedObservations, observations)
+			}
+		})
+	}
+}
+
+func TestCSPLearner_Observe(t *testing.T) {
+	t.Parallel()
+
+	const document = "https://example.com/page"
+
+	testCases := []struct {
+		name           string
+		violation      CSPViolation
+		expectedSource CSPSource // empty if the violation is ignored
+	}{
+		{name: "Same Origin", violation: CSPViolation{document, DirectiveImgSrc, "https://example.com/logo.png"}, expectedSource: SourceSelf},
+		{name: "Same Origin, Explicit Port", violation: CSPViolation{document, DirectiveImgSrc, "https://example.com:443/logo.png"}, expectedSource: SourceSelf},
+		{name: "Same Host, WebSocket", violation: CSPViolation{document, DirectiveConnectSrc, "wss://example.com/socket"}, expectedSource: "wss://example.com"},
+		{name: "Legacy Self", violation: CSPViolation{document, DirectiveImgSrc, "self"}, expectedSource: SourceSelf},
+		{name: "Other Origin", violation: CSPViolation{document, DirectiveImgSrc, "https://img.example.net/a.png?b=c"}, expectedSource: "https://img.example.net"},
+		{name: "Other Port", violation: CSPViolation{document, DirectiveConnectSrc, "https://example.com:8443/api"}, expectedSource: "https://example.com:8443"},
+		{name: "Upper Case", violation: CSPViolation{document, "IMG-SRC", "HTTPS://IMG.EXAMPLE.NET/A.PNG"}, expectedSource: "https://img.example.net"},
+		{name: "IPv6", violation: CSPViolation{document, DirectiveConnectSrc, "http://[::1]:8080/"}, expectedSource: "http://[::1]:8080"},
+		{name: "Insecure Downgrade", violation: CSPViolation{document, DirectiveImgSrc, "http://example.com/logo.png"}, expectedSource: "http://example.com"},
+		{name: "Data", violation: CSPViolation{document, DirectiveFontSrc, "data"}, expectedSource: SourceData},
+		{name: "Blob URL", violation: CSPViolation{document, DirectiveWorkerSrc, "blob:https://example.com/uuid"}, expectedSource: SourceBlob},
+		{name: "Inline", violation: CSPViolation{document, DirectiveScriptSrcAttr, "inline"}, expectedSource: SourceUnsafeInline},
+		{name: "Eval", violation: CSPViolation{document, DirectiveScriptSrc, "eval"}, expectedSource: SourceUnsafeEval},
+		{name: "WebAssembly", violation: CSPViolation{document, DirectiveScriptSrc, "wasm-eval"}, expectedSource: SourceWasmUnsafeEval},
+		{name: "Frame Ancestor", violation: CSPViolation{document, DirectiveFrameAncestors, document}},
+		{name: "Unlearned Directive", violation: CSPViolation{document, DirectiveTrustedTypes, "trusted-types-policy"}},
+		{name: "Empty Blocked URL", violation: CSPViolation{document, DirectiveImgSrc, ""}},
+		{name: "Relative Blocked URL", violation: CSPViolation{document, DirectiveImgSrc, "/logo.png"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			learner := NewCSPLearner()
+			learner.Observe(tc.violation)
+			observations := learner.Observations()
+
+			if tc.expectedSource == "" {
+				if len(observations) != 0 {
+					t.Errorf("Violation should be ignored\tActual: %v\n", observations)
+				}
+				return
+			}
+
+			if len(observations) != 1 || observations[0].Source != tc.expectedSource {
+				t.Errorf("Expected: %s\tActual: %v\n", tc.expectedSource, observations)
+			}
+		})
+	}
+}
+
+func TestCSPLearner_Observations(t *testing.T) {
+	t.Parallel()
+
+	learner := NewCSPLearner()
+	for _, blocked := range []string{"https://b.example.net/", "https://a.example.net/", "https://b.example.net/x", "https://example.com/"} {
+		learner.Observe(CSPViolation{"https://example.com/", DirectiveScriptSrcElem, blocked})
+	}
+	learner.Observe(CSPViolation{"https://example.com/", DirectiveConnectSrc, "https://api.example.net/"})
+
+	expected := []CSPObservation{
+		{Directive: DirectiveConnectSrc, Source: "https://api.example.net", Count: 1},
+		{Directive: DirectiveScriptSrc, Source: "https://b.example.net", Count: 2},
+		{Directive: DirectiveScriptSrc, Source: SourceSelf, Count: 1},
+		{Directive: DirectiveScriptSrc, Source: "https://a.example.net", Count: 1},
+	}
+
+	observations := learner.Observations()
+	if !reflect.DeepEqual(observations, expected) {
+		t.Errorf("Expected: %v\tActual: %v\n", expected, observations)
+	}
+}
+
+func TestCSPLearner_Propose(t *testing.T) {
+	t.Parallel()
+
+	learner := NewCSPLearner()
+	learner.MaxHostsPerScheme = 2
+
+	violations := []CSPViolation{
+		{"https://example.com/", DirectiveScriptSrcElem, "https://example.com/app.js"},
+		{"https://example.com/", DirectiveScriptSrcElem, "https://cdn.example.net/lib.js"},
+		{"https://example.com/", DirectiveFormAction, "https://example.com/login"},
+		{"https://example.com/", DirectiveStyleSrcAttr, "inline"},
+	}
+	// three image hosts are collapsed into https:
+	for i := 0; i < 3; i++ {
+		violations = append(violations, CSPViolation{"https://example.com/", DirectiveImgSrc, fmt.Sprintf("https://img%d.example.net/a.png", i)})
+	}
+	violations = append(violations, CSPViolation{"https://example.com/", DirectiveImgSrc, "data"})
+
+	for _, violation := range violations {
+		learner.Observe(violation)
+	}
+
+	expected := map[CSPDirective][]CSPSource{
+		DirectiveDefaultSrc: {SourceNone},
+		DirectiveBaseURI:    {SourceNone},
+		DirectiveFormAction: {SourceSelf},
+		DirectiveScriptSrc:  {SourceSelf, "https://cdn.example.net"},
+		DirectiveStyleSrc:   {SourceUnsafeInline},
+		DirectiveImgSrc:     {SourceData, SourceHTTPS},
+	}
+
+	csp := learner.Propose()
+	if !reflect.DeepEqual(csp.policies, expected) {
+		t.Errorf("Expected: %v\tActual: %v\n", expected, csp.policies)
+	}
+}
+
+func TestCSPLearner_Propose_empty(t *testing.T) {
+	t.Parallel()
+
+	csp := NewCSPLearner().Propose()
+
+	expected := CSPLearningPolicy("")
+	expected.Remove(DeprecatedDirectiveReportURI)
+	if !reflect.DeepEqual(csp.policies, expected.policies) {
+		t.Errorf("Expected: %v\tActual: %v\n", expected.policies, csp.policies)
+	}
+}
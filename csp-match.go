@@ -0,0 +1,336 @@
+package helmet
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"net/url"
+	"strings"
+)
+
+// CSPMatchOptions describes the document and element that a Content-Security-Policy is evaluated for.
+type CSPMatchOptions struct {
+	// Origin is the origin of the document that the policy protects, e.g. "https://example.com".
+	// 'self' never matches without it, and sources without a scheme then match both http and https.
+	Origin string
+
+	// Nonce is the nonce attribute of the <script> or <style> element that loads the resource, if any.
+	Nonce string
+
+	// Content is the content of an inline script or style, which hash sources are compared against.
+	Content []byte
+}
+
+// CSPDecision represents whether a Content-Security-Policy allows a resource, and why.
+type CSPDecision struct {
+	Allowed   bool
+	Directive CSPDirective // the directive that decided, empty if no directive applies to the resource
+	Source    CSPSource    // the source that allowed the resource, empty if it was blocked
+}
+
+// Allows returns whether the Content-Security-Policy allows the given resource URL to be loaded for the given directive,
+// after falling back to other directives as CSP Level 3 does (e.g. script-src-elem to script-src to default-src).
+// An empty resource is evaluated as inline content, against nonces, hashes and 'unsafe-inline'.
+func (csp *ContentSecurityPolicy) Allows(directive CSPDirective, resource string, opts CSPMatchOptions) CSPDecision {
+	resolution := csp.Resolve(directive)
+	if resolution.Effective == "" {
+		return CSPDecision{Allowed: true}
+	}
+	effective, sources := resolution.Effective, honouredCSPSources(directive, resolution.Sources)
+
+	inline := resource == "" || directive == DirectiveScriptSrcAttr || directive == DirectiveStyleSrcAttr
+	if inline {
+		return csp.allowsInline(directive, effective, sources, opts)
+	}
+
+	if source, ok := matchCSPNonce(directive, sources, opts.Nonce); ok {
+		return CSPDecision{true, effective, source}
+	}
+
+	u, err := url.Parse(resource)
+	if err != nil {
+		return CSPDecision{false, effective, ""}
+	}
+
+	var origin *url.URL
+	if opts.Origin != "" {
+		if origin, err = url.Parse(opts.Origin); err != nil {
+			origin = nil
+		}
+	}
+
+	// relative URLs are resolved against the document, like browsers do
+	if origin != nil {
+		u = origin.ResolveReference(u)
+	}
+
+	for _, source := range sources {
+		if matchCSPSource(source, u, origin) {
+			return CSPDecision{true, effective, source}
+		}
+	}
+
+	return CSPDecision{false, effective, ""}
+}
+
+// allowsInline evaluates inline content against the sources of the effective directive.
+func (csp *ContentSecurityPolicy) allowsInline(directive, effective CSPDirective, sources []CSPSource, opts CSPMatchOptions) CSPDecision {
+	if source, ok := matchCSPNonce(directive, sources, opts.Nonce); ok {
+		return CSPDecision{true, effective, source}
+	}
+
+	// hashes only apply to attributes when 'unsafe-hashes' is present
+	attribute := directive == DirectiveScriptSrcAttr || directive == DirectiveStyleSrcAttr
+	if !attribute || hasCSPSource(sources, SourceUnsafeHashes) {
+		for _, source := range sources {
+			if matchCSPHash(source, opts.Content) {
+				return CSPDecision{true, effective, source}
+			}
+		}
+	}
+
+	if hasCSPSource(sources, SourceUnsafeInline) {
+		return CSPDecision{true, effective, SourceUnsafeInline}
+	}
+
+	return CSPDecision{false, effective, ""}
+}
+
+// isScriptDirective returns whether the directive governs scripts, which is where 'strict-dynamic' applies.
+func isScriptDirective(directive CSPDirective) bool {
+	switch directive {
+	case DirectiveScriptSrc, DirectiveScriptSrcElem, DirectiveScriptSrcAttr, DirectiveWorkerSrc:
+		return true
+	default:
+		return false
+	}
+}
+
+// honouredCSPSources returns the sources that browsers honour for the given directive: 'unsafe-inline' is ignored
+// as soon as a nonce, a hash or 'strict-dynamic' is present, and 'strict-dynamic' makes them ignore every host and
+// scheme source, and 'self', so that only nonces and hashes remain.
+func honouredCSPSources(directive CSPDirective, sources []CSPSource) []CSPSource {
+	strictDynamic := isScriptDirective(directive) && hasCSPSource(sources, SourceStrictDynamic)
+	inlineIgnored := strictDynamic || hasNonceOrHash(sources)
+
+	honoured := []CSPSource{}
+	for _, source := range sources {
+		switch {
+		case inlineIgnored && strings.EqualFold(string(source), string(SourceUnsafeInline)):
+		case strictDynamic && (strings.EqualFold(string(source), string(SourceSelf)) || !strings.HasPrefix(string(source), "'")):
+		default:
+			honoured = append(honoured, source)
+		}
+	}
+	return honoured
+}
+
+// matchCSPNonce returns the nonce source that allows an element with the given nonce. Only <script> and <style>
+// elements have nonces, so attributes and other resources never match one.
+func matchCSPNonce(directive CSPDirective, sources []CSPSource, nonce string) (CSPSource, bool) {
+	switch directive {
+	case DirectiveScriptSrc, DirectiveScriptSrcElem, DirectiveStyleSrc, DirectiveStyleSrcElem:
+	default:
+		return "", false
+	}
+
+	if nonce == "" {
+		return "", false
+	}
+
+	// nonces are compared case-sensitively, unlike keywords
+	for _, source := range sources {
+		if source == CSPSource("'nonce-"+nonce+"'") {
+			return source, true
+		}
+	}
+	return "", false
+}
+
+func hasCSPSource(sources []CSPSource, source CSPSource) bool {
+	for _, s := range sources {
+		if strings.EqualFold(string(s), string(source)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasNonceOrHash(sources []CSPSource) bool {
+	for _, source := range sources {
+		s := strings.ToLower(string(source))
+		if strings.HasPrefix(s, "'nonce-") || strings.HasPrefix(s, "'sha256-") || strings.HasPrefix(s, "'sha384-") || strings.HasPrefix(s, "'sha512-") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCSPHash returns whether the given source is a hash source of the given content.
+func matchCSPHash(source CSPSource, content []byte) bool {
+	s := string(source)
+	if len(s) < 2 || s[0] != '\'' || s[len(s)-1] != '\'' {
+		return false
+	}
+
+	algorithm, digest, ok := strings.Cut(s[1:len(s)-1], "-")
+	if !ok {
+		return false
+	}
+
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		h = sha256.New()
+	case "sha384":
+		h = sha512.New384()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return false
+	}
+	h.Write(content)
+
+	// base64url digests are equivalent to base64 ones
+	digest = strings.NewReplacer("-", "+", "_", "/").Replace(digest)
+	return strings.TrimRight(digest, "=") == strings.TrimRight(base64.StdEncoding.EncodeToString(h.Sum(nil)), "=")
+}
+
+// matchCSPSource returns whether the given source expression matches the given URL, for a document with the given origin.
+func matchCSPSource(source CSPSource, u *url.URL, origin *url.URL) bool {
+	s := strings.ToLower(string(source))
+
+	switch {
+	case s == string(SourceWildcard):
+		// the wildcard only matches network schemes, or the document's own one
+		return u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "ws" || u.Scheme == "wss" ||
+			(origin != nil && u.Scheme == origin.Scheme)
+	case s == string(SourceSelf):
+		if origin == nil {
+			return false
+		}
+		return strings.EqualFold(u.Hostname(), origin.Hostname()) &&
+			matchCSPScheme(origin.Scheme, u.Scheme) &&
+			matchCSPPort(effectivePort(origin), u)
+	case strings.HasPrefix(s, "'"):
+		// keywords, nonces and hashes never match URLs
+		return false
+	case strings.HasSuffix(s, ":") && !strings.Contains(s, "/"):
+		return matchCSPScheme(strings.TrimSuffix(s, ":"), u.Scheme)
+	default:
+		return matchCSPHostSource(string(source), u, origin)
+	}
+}
+
+// matchCSPHostSource returns whether the given host source, e.g. "https://*.example.com:443/js/", matches the given URL.
+func matchCSPHostSource(source string, u *url.URL, origin *url.URL) bool {
+	scheme, rest, ok := strings.Cut(source, "://")
+	scheme = strings.ToLower(scheme)
+	if !ok {
+		rest = source
+		scheme = "http"
+		if origin != nil {
+			scheme = origin.Scheme
+		}
+	}
+	if !matchCSPScheme(scheme, u.Scheme) {
+		return false
+	}
+
+	path := ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest, path = rest[:i], rest[i:]
+	}
+
+	host, port := rest, ""
+	if i := strings.LastIndex(rest, ":"); i >= 0 && !strings.HasSuffix(rest, "]") {
+		host, port = rest[:i], rest[i+1:]
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+
+	if !matchCSPHost(host, strings.ToLower(u.Hostname())) {
+		return false
+	}
+
+	if port != "*" && !matchCSPPort(port, u) {
+		return false
+	}
+
+	if path != "" && path != "/" {
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			path = unescaped
+		}
+
+		if strings.HasSuffix(path, "/") {
+			return strings.HasPrefix(u.Path, path)
+		}
+		return u.Path == path
+	}
+	return true
+}
+
+// matchCSPHost returns whether the given host source host, possibly with a leading "*." wildcard, matches the given host.
+func matchCSPHost(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	if strings.HasPrefix(pattern, "*.") {
+		// the wildcard matches subdomains, but not the domain itself
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return pattern == host
+}
+
+// matchCSPScheme returns whether a source's scheme matches the URL's scheme, allowing secure upgrades.
+func matchCSPScheme(source, scheme string) bool {
+	scheme = strings.ToLower(scheme)
+
+	switch source {
+	case scheme:
+		return true
+	case "http":
+		return scheme == "https"
+	case "ws":
+		return scheme == "wss" || scheme == "http" || scheme == "https"
+	case "wss":
+		return scheme == "https"
+	default:
+		return false
+	}
+}
+
+// matchCSPPort returns whether a source's port, empty for the default one, matches the URL's port.
+func matchCSPPort(port string, u *url.URL) bool {
+	if port == "" {
+		port = defaultPort(u.Scheme)
+	}
+
+	actual := effectivePort(u)
+	if port == actual {
+		return true
+	}
+
+	// an upgrade from the default http port to the default https one is allowed
+	return port == "80" && actual == "443" && u.Port() == ""
+}
+
+// effectivePort returns the port of the URL, or the default one for its scheme.
+func effectivePort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	return defaultPort(u.Scheme)
+}
+
+func defaultPort(scheme string) string {
+	switch strings.ToLower(scheme) {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	default:
+		return ""
+	}
+}
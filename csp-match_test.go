@@ -0,0 +1,296 @@
+package helmet
+
+import "testing"
+
+func TestContentSecurityPolicy_Allows(t *testing.T) {
+	t.Parallel()
+
+	const origin = "https://example.com"
+	const alertHash CSPSource = "'sha256-bhHHL3z2vDgxUt0W3dWQOrprscmda2Y5pLsLg4GF+pI='"
+
+	testCases := []struct {
+		name             string
+		policies         map[CSPDirective][]CSPSource
+		directive        CSPDirective
+		resource         string
+		opts             CSPMatchOptions
+		expectedDecision CSPDecision
+	}{
+		{
+			name:             "No Directive",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceSelf}},
+			directive:        DirectiveScriptSrc,
+			resource:         "https://evil.com/x.js",
+			expectedDecision: CSPDecision{Allowed: true},
+		},
+		{
+			name:             "Self",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://example.com/logo.png",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{true, DirectiveDefaultSrc, SourceSelf},
+		},
+		{
+			name:             "Self, Relative URL",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}},
+			directive:        DirectiveImgSrc,
+			resource:         "/logo.png",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{true, DirectiveDefaultSrc, SourceSelf},
+		},
+		{
+			name:             "Self, Other Origin",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://evil.com/logo.png",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{false, DirectiveDefaultSrc, ""},
+		},
+		{
+			name:             "Self, Upgrade To HTTPS",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://example.com/logo.png",
+			opts:             CSPMatchOptions{Origin: "http://example.com"},
+			expectedDecision: CSPDecision{true, DirectiveDefaultSrc, SourceSelf},
+		},
+		{
+			name:             "Self, Without Origin",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://example.com/logo.png",
+			expectedDecision: CSPDecision{false, DirectiveDefaultSrc, ""},
+		},
+		{
+			name:             "Fallback Script Src Elem To Script Src",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"https://cdn.example.com"}, DirectiveDefaultSrc: {SourceNone}},
+			directive:        DirectiveScriptSrcElem,
+			resource:         "https://cdn.example.com/app.js",
+			expectedDecision: CSPDecision{true, DirectiveScriptSrc, "https://cdn.example.com"},
+		},
+		{
+			name:             "Fallback Worker Src To Child Src",
+			policies:         map[CSPDirective][]CSPSource{DirectiveChildSrc: {SourceNone}, DirectiveScriptSrc: {SourceWildcard}},
+			directive:        DirectiveWorkerSrc,
+			resource:         "https://example.com/worker.js",
+			expectedDecision: CSPDecision{false, DirectiveChildSrc, ""},
+		},
+		{
+			name:             "Scheme Source",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceData}},
+			directive:        DirectiveImgSrc,
+			resource:         "data:image/png;base64,AAAA",
+			expectedDecision: CSPDecision{true, DirectiveImgSrc, SourceData},
+		},
+		{
+			name:             "Scheme Source, Upgrade",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceHTTP}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://anything.com/logo.png",
+			expectedDecision: CSPDecision{true, DirectiveImgSrc, SourceHTTP},
+		},
+		{
+			name:             "Scheme Source, No Downgrade",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceHTTPS}},
+			directive:        DirectiveImgSrc,
+			resource:         "http://anything.com/logo.png",
+			expectedDecision: CSPDecision{false, DirectiveImgSrc, ""},
+		},
+		{
+			name:             "Wildcard, Not Data",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceWildcard}},
+			directive:        DirectiveImgSrc,
+			resource:         "data:image/png;base64,AAAA",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{false, DirectiveImgSrc, ""},
+		},
+		{
+			name:             "Wildcard Subdomain",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {"*.cdn.com"}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://eu.static.cdn.com/logo.png",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{true, DirectiveImgSrc, "*.cdn.com"},
+		},
+		{
+			name:             "Wildcard Subdomain, Not Apex",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {"*.cdn.com"}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://cdn.com/logo.png",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{false, DirectiveImgSrc, ""},
+		},
+		{
+			name:             "Host Source Without Scheme, HTTPS Document",
+			policies:         map[CSPDirective][]CSPSource{DirectiveImgSrc: {"cdn.com"}},
+			directive:        DirectiveImgSrc,
+			resource:         "http://cdn.com/logo.png",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{false, DirectiveImgSrc, ""},
+		},
+		{
+			name:             "Port, Default",
+			policies:         map[CSPDirective][]CSPSource{DirectiveConnectSrc: {"https://api.com"}},
+			directive:        DirectiveConnectSrc,
+			resource:         "https://api.com:8443/v1",
+			expectedDecision: CSPDecision{false, DirectiveConnectSrc, ""},
+		},
+		{
+			name:             "Port, Explicit",
+			policies:         map[CSPDirective][]CSPSource{DirectiveConnectSrc: {"https://api.com:8443"}},
+			directive:        DirectiveConnectSrc,
+			resource:         "https://api.com:8443/v1",
+			expectedDecision: CSPDecision{true, DirectiveConnectSrc, "https://api.com:8443"},
+		},
+		{
+			name:             "Port, Wildcard",
+			policies:         map[CSPDirective][]CSPSource{DirectiveConnectSrc: {"https://api.com:*"}},
+			directive:        DirectiveConnectSrc,
+			resource:         "https://api.com:9000/v1",
+			expectedDecision: CSPDecision{true, DirectiveConnectSrc, "https://api.com:*"},
+		},
+		{
+			name:             "Path, Prefix",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"https://cdn.com/js/"}},
+			directive:        DirectiveScriptSrc,
+			resource:         "https://cdn.com/js/app.js",
+			expectedDecision: CSPDecision{true, DirectiveScriptSrc, "https://cdn.com/js/"},
+		},
+		{
+			name:             "Path, Exact Mismatch",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"https://cdn.com/js/app.js"}},
+			directive:        DirectiveScriptSrc,
+			resource:         "https://cdn.com/js/other.js",
+			expectedDecision: CSPDecision{false, DirectiveScriptSrc, ""},
+		},
+		{
+			name:             "Nonce",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"'nonce-abc123'"}},
+			directive:        DirectiveScriptSrcElem,
+			resource:         "https://anything.com/app.js",
+			opts:             CSPMatchOptions{Nonce: "abc123"},
+			expectedDecision: CSPDecision{true, DirectiveScriptSrc, "'nonce-abc123'"},
+		},
+		{
+			name:             "Nonce, Case Sensitive",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"'nonce-abc123'"}},
+			directive:        DirectiveScriptSrc,
+			opts:             CSPMatchOptions{Nonce: "ABC123"},
+			expectedDecision: CSPDecision{false, DirectiveScriptSrc, ""},
+		},
+		{
+			name:             "Nonce, Inline Style",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {"'nonce-abc123'"}},
+			directive:        DirectiveStyleSrcElem,
+			opts:             CSPMatchOptions{Nonce: "abc123"},
+			expectedDecision: CSPDecision{true, DirectiveDefaultSrc, "'nonce-abc123'"},
+		},
+		{
+			name:             "Nonce, Script Attribute",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"'nonce-abc123'"}},
+			directive:        DirectiveScriptSrcAttr,
+			opts:             CSPMatchOptions{Nonce: "abc123"},
+			expectedDecision: CSPDecision{false, DirectiveScriptSrc, ""},
+		},
+		{
+			name:             "Nonce, Style Attribute",
+			policies:         map[CSPDirective][]CSPSource{DirectiveStyleSrc: {"'nonce-abc123'"}},
+			directive:        DirectiveStyleSrcAttr,
+			opts:             CSPMatchOptions{Nonce: "abc123"},
+			expectedDecision: CSPDecision{false, DirectiveStyleSrc, ""},
+		},
+		{
+			name:             "Nonce, Image",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {"'nonce-abc123'"}},
+			directive:        DirectiveImgSrc,
+			resource:         "https://anything.com/a.png",
+			opts:             CSPMatchOptions{Nonce: "abc123"},
+			expectedDecision: CSPDecision{false, DirectiveDefaultSrc, ""},
+		},
+		{
+			name:             "Nonce, Connect",
+			policies:         map[CSPDirective][]CSPSource{DirectiveConnectSrc: {"'nonce-abc123'"}},
+			directive:        DirectiveConnectSrc,
+			resource:         "https://anything.com/api",
+			opts:             CSPMatchOptions{Nonce: "abc123"},
+			expectedDecision: CSPDecision{false, DirectiveConnectSrc, ""},
+		},
+		{
+			name:             "Hash",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {alertHash}},
+			directive:        DirectiveScriptSrc,
+			opts:             CSPMatchOptions{Content: []byte("alert(1)")},
+			expectedDecision: CSPDecision{true, DirectiveScriptSrc, alertHash},
+		},
+		{
+			name:             "Hash, Other Content",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {alertHash}},
+			directive:        DirectiveScriptSrc,
+			opts:             CSPMatchOptions{Content: []byte("alert(2)")},
+			expectedDecision: CSPDecision{false, DirectiveScriptSrc, ""},
+		},
+		{
+			name:             "Hash, Attribute Without Unsafe Hashes",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {alertHash}},
+			directive:        DirectiveScriptSrcAttr,
+			opts:             CSPMatchOptions{Content: []byte("alert(1)")},
+			expectedDecision: CSPDecision{false, DirectiveScriptSrc, ""},
+		},
+		{
+			name:             "Hash, Attribute With Unsafe Hashes",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {SourceUnsafeHashes, alertHash}},
+			directive:        DirectiveScriptSrcAttr,
+			opts:             CSPMatchOptions{Content: []byte("alert(1)")},
+			expectedDecision: CSPDecision{true, DirectiveScriptSrc, alertHash},
+		},
+		{
+			name:             "Unsafe Inline",
+			policies:         map[CSPDirective][]CSPSource{DirectiveStyleSrc: {SourceSelf, SourceUnsafeInline}},
+			directive:        DirectiveStyleSrcElem,
+			expectedDecision: CSPDecision{true, DirectiveStyleSrc, SourceUnsafeInline},
+		},
+		{
+			name:             "Unsafe Inline, Ignored With Nonce",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {SourceUnsafeInline, "'nonce-abc123'"}},
+			directive:        DirectiveScriptSrc,
+			expectedDecision: CSPDecision{false, DirectiveScriptSrc, ""},
+		},
+		{
+			name:             "Strict Dynamic Ignores Hosts",
+			policies:         map[CSPDirective][]CSPSource{DirectiveScriptSrc: {SourceStrictDynamic, "https://cdn.com", "'nonce-abc123'"}},
+			directive:        DirectiveScriptSrc,
+			resource:         "https://cdn.com/app.js",
+			expectedDecision: CSPDecision{false, DirectiveScriptSrc, ""},
+		},
+		{
+			name:             "None",
+			policies:         map[CSPDirective][]CSPSource{DirectiveObjectSrc: {SourceNone}},
+			directive:        DirectiveObjectSrc,
+			resource:         "https://example.com/flash.swf",
+			opts:             CSPMatchOptions{Origin: origin},
+			expectedDecision: CSPDecision{false, DirectiveObjectSrc, ""},
+		},
+		{
+			name:             "Non Fetch Directive Doesn't Fall Back",
+			policies:         map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceNone}},
+			directive:        DirectiveFormAction,
+			resource:         "https://evil.com/login",
+			expectedDecision: CSPDecision{Allowed: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csp := NewContentSecurityPolicy(tc.policies)
+
+			decision := csp.Allows(tc.directive, tc.resource, tc.opts)
+			if decision != tc.expectedDecision {
+				t.Errorf("Expected: %+v\tActual: %+v\n", tc.expectedDecision, decision)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package helmet
+
+import "strings"
+
+// Merge adds the directives and sources of the other Content-Security-Policies, so that the result allows
+// everything that any of them explicitly allows:
+//
+//   - directives that fall back to others, e.g. script-src to default-src, are merged after resolving each policy's
+//     effective sources, so that merging script-src into a policy that only sets default-src keeps default-src's sources
+//   - sources are deduplicated, after keywords and schemes are normalized to lower case
+//   - 'none' is dropped from directives that end up with other sources
+func (csp *ContentSecurityPolicy) Merge(others ...*ContentSecurityPolicy) {
+	for _, other := range others {
+		if other == nil {
+			continue
+		}
+
+		// resolve against both policies before either changes
+		merged := map[CSPDirective][]CSPSource{}
+		for _, policy := range []*ContentSecurityPolicy{csp, other} {
+			for directive := range policy.policies {
+				if _, ok := merged[directive]; ok {
+					continue
+				}
+
+				merged[directive] = append(
+					csp.Resolve(directive).Sources,
+					other.Resolve(directive).Sources...,
+				)
+			}
+		}
+
+		for directive, sources := range merged {
+			csp.policies[directive] = normalizeCSPSources(sources)
+		}
+		csp.AddNonce(other.nonces...)
+	}
+
+	csp.cache = ""
+}
+
+// normalizeCSPSources lower cases keywords and schemes, removes duplicates, and drops 'none' if there are other sources.
+func normalizeCSPSources(sources []CSPSource) []CSPSource {
+	seen := map[CSPSource]bool{}
+	normalized := []CSPSource{}
+
+	for _, source := range sources {
+		source = normalizeCSPSource(source)
+		if seen[source] {
+			continue
+		}
+		seen[source] = true
+		normalized = append(normalized, source)
+	}
+
+	if len(normalized) > 1 {
+		withoutNone := []CSPSource{}
+		for _, source := range normalized {
+			if source != SourceNone {
+				withoutNone = append(withoutNone, source)
+			}
+		}
+		normalized = withoutNone
+	}
+
+	return normalized
+}
+
+// normalizeCSPSource lower cases keywords, e.g. 'SELF', and schemes, e.g. HTTPS:, but not nonces, hashes or paths.
+func normalizeCSPSource(source CSPSource) CSPSource {
+	s := string(source)
+	lower := strings.ToLower(s)
+
+	switch {
+	case strings.HasPrefix(lower, "'nonce-"), strings.HasPrefix(lower, "'sha256-"), strings.HasPrefix(lower, "'sha384-"), strings.HasPrefix(lower, "'sha512-"):
+		// nonce and hash values are case-sensitive
+		i := strings.Index(s, "-")
+		return CSPSource(lower[:i] + s[i:])
+	case strings.HasPrefix(s, "'"):
+		return CSPSource(lower)
+	case strings.HasSuffix(s, ":") && !strings.Contains(s, "/"):
+		return CSPSource(lower)
+	}
+
+	// host sources are case-insensitive up to their path
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok {
+		scheme, rest = "", s
+	}
+
+	host, path := rest, ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		host, path = rest[:i], rest[i:]
+	}
+
+	if ok {
+		return CSPSource(strings.ToLower(scheme) + "://" + strings.ToLower(host) + path)
+	}
+	return CSPSource(strings.ToLower(host) + path)
+}
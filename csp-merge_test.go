@@ -0,0 +1,98 @@
+package helmet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContentSecurityPolicy_Merge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		base             map[CSPDirective][]CSPSource
+		others           []map[CSPDirective][]CSPSource
+		expectedPolicies map[CSPDirective][]CSPSource
+	}{
+		{
+			name:             "Union",
+			base:             map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceSelf}},
+			others:           []map[CSPDirective][]CSPSource{{DirectiveImgSrc: {"https://maps.example.com"}, DirectiveConnectSrc: {"https://api.example.com"}}},
+			expectedPolicies: map[CSPDirective][]CSPSource{DirectiveImgSrc: {SourceSelf, "https://maps.example.com"}, DirectiveConnectSrc: {"https://api.example.com"}},
+		},
+		{
+			name:             "Dedupe, Keyword Normalization",
+			base:             map[CSPDirective][]CSPSource{DirectiveScriptSrc: {SourceSelf, "HTTPS:"}},
+			others:           []map[CSPDirective][]CSPSource{{DirectiveScriptSrc: {"'SELF'", SourceHTTPS, "https://CDN.example.com/Path/"}}},
+			expectedPolicies: map[CSPDirective][]CSPSource{DirectiveScriptSrc: {SourceSelf, SourceHTTPS, "https://cdn.example.com/Path/"}},
+		},
+		{
+			name:             "None Dropped",
+			base:             map[CSPDirective][]CSPSource{DirectiveObjectSrc: {SourceNone}},
+			others:           []map[CSPDirective][]CSPSource{{DirectiveObjectSrc: {"https://pay.example.com"}}},
+			expectedPolicies: map[CSPDirective][]CSPSource{DirectiveObjectSrc: {"https://pay.example.com"}},
+		},
+		{
+			name:             "None Kept",
+			base:             map[CSPDirective][]CSPSource{DirectiveObjectSrc: {SourceNone}},
+			others:           []map[CSPDirective][]CSPSource{{DirectiveObjectSrc: {"'NONE'"}}},
+			expectedPolicies: map[CSPDirective][]CSPSource{DirectiveObjectSrc: {SourceNone}},
+		},
+		{
+			name:   "Fallback",
+			base:   map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}},
+			others: []map[CSPDirective][]CSPSource{{DirectiveScriptSrc: {"https://analytics.example.com"}}},
+			expectedPolicies: map[CSPDirective][]CSPSource{
+				DirectiveDefaultSrc: {SourceSelf},
+				DirectiveScriptSrc:  {SourceSelf, "https://analytics.example.com"},
+			},
+		},
+		{
+			name:             "Nonces Keep Case",
+			base:             map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"'nonce-AbC'"}},
+			others:           []map[CSPDirective][]CSPSource{{DirectiveScriptSrc: {"'NONCE-abc'"}}},
+			expectedPolicies: map[CSPDirective][]CSPSource{DirectiveScriptSrc: {"'nonce-AbC'", "'nonce-abc'"}},
+		},
+		{
+			name: "Multiple",
+			base: map[CSPDirective][]CSPSource{DirectiveUpgradeInsecureRequests: {}},
+			others: []map[CSPDirective][]CSPSource{
+				{DirectiveFrameSrc: {"https://pay.example.com"}},
+				{DirectiveFrameSrc: {"https://maps.example.com"}},
+				nil,
+			},
+			expectedPolicies: map[CSPDirective][]CSPSource{
+				DirectiveUpgradeInsecureRequests: {},
+				DirectiveFrameSrc:                {"https://pay.example.com", "https://maps.example.com"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csp := NewContentSecurityPolicy(tc.base)
+			_ = csp.String() // populate the cache
+
+			others := []*ContentSecurityPolicy{}
+			for _, policies := range tc.others {
+				if policies == nil {
+					others = append(others, nil)
+				} else {
+					others = append(others, NewContentSecurityPolicy(policies))
+				}
+			}
+			csp.Merge(others...)
+
+			if !reflect.DeepEqual(csp.policies, tc.expectedPolicies) {
+				t.Errorf("Expected: %v\tActual: %v\n", tc.expectedPolicies, csp.policies)
+			}
+
+			if csp.cache != "" {
+				t.Errorf("Cache should be reset\tActual: %s\n", csp.cache)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package helmet
+
+import (
+	"fmt"
+	"html"
+)
+
+// cspMetaUnsupported are the directives that browsers ignore in a <meta> element.
+var cspMetaUnsupported = []CSPDirective{DirectiveFrameAncestors, DeprecatedDirectiveReportURI, DirectiveSandbox}
+
+// MetaTag renders the Content-Security-Policy as a <meta http-equiv="Content-Security-Policy"> element, for pages
+// that are served without control over their HTTP headers. It drops the directives that browsers ignore in a
+// <meta> element, and returns which of them were dropped so that they can be enforced some other way.
+// The element should be placed in the <head>, before any content it is meant to restrict.
+// It is empty if no directive is left to render, since an empty policy would restrict nothing.
+func (csp *ContentSecurityPolicy) MetaTag() (string, []CSPDirective) {
+	meta := csp.Clone()
+
+	dropped := []CSPDirective{}
+	for _, directive := range cspMetaUnsupported {
+		if _, ok := meta.policies[directive]; ok {
+			dropped = append(dropped, directive)
+		}
+	}
+	meta.Remove(dropped...)
+
+	policy := meta.String()
+	if policy == "" {
+		return "", dropped
+	}
+	return fmt.Sprintf(`<meta http-equiv="%s" content="%s">`, HeaderContentSecurityPolicy, html.EscapeString(policy)), dropped
+}
@@ -0,0 +1,70 @@
+package helmet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContentSecurityPolicy_MetaTag(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		policies        map[CSPDirective][]CSPSource
+		expectedTag     string
+		expectedDropped []CSPDirective
+	}{
+		{
+			name:            "Empty",
+			policies:        map[CSPDirective][]CSPSource{},
+			expectedTag:     "",
+			expectedDropped: []CSPDirective{},
+		},
+		{
+			name:            "Supported",
+			policies:        map[CSPDirective][]CSPSource{DirectiveScriptSrc: {SourceSelf}},
+			expectedTag:     `<meta http-equiv="Content-Security-Policy" content="script-src &#39;self&#39;">`,
+			expectedDropped: []CSPDirective{},
+		},
+		{
+			name: "Unsupported",
+			policies: map[CSPDirective][]CSPSource{
+				DirectiveDefaultSrc:          {SourceNone},
+				DirectiveFrameAncestors:      {SourceNone},
+				DeprecatedDirectiveReportURI: {"/csp-reports"},
+				DirectiveSandbox:             {SandboxAllowScripts},
+			},
+			expectedTag:     `<meta http-equiv="Content-Security-Policy" content="default-src &#39;none&#39;">`,
+			expectedDropped: []CSPDirective{DirectiveFrameAncestors, DeprecatedDirectiveReportURI, DirectiveSandbox},
+		},
+		{
+			name:            "Escaped",
+			policies:        map[CSPDirective][]CSPSource{DirectiveImgSrc: {`https://example.com/"><script>`}},
+			expectedTag:     `<meta http-equiv="Content-Security-Policy" content="img-src https://example.com/&#34;&gt;&lt;script&gt;">`,
+			expectedDropped: []CSPDirective{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			csp := NewContentSecurityPolicy(tc.policies)
+
+			tag, dropped := csp.MetaTag()
+			if tag != tc.expectedTag {
+				t.Errorf("Incorrect tag\tExpected: %s\tActual: %s\n", tc.expectedTag, tag)
+			}
+
+			if !reflect.DeepEqual(dropped, tc.expectedDropped) {
+				t.Errorf("Incorrect dropped directives\tExpected: %v\tActual: %v\n", tc.expectedDropped, dropped)
+			}
+
+			// the original is untouched
+			if len(csp.policies) != len(tc.policies) {
+				t.Errorf("MetaTag should not change the Content-Security-Policy\tActual: %s\n", csp)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package helmet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+)
+
+// cspNonceKey is the context key of the per-request Content-Security-Policy nonce.
+type cspNonceKey struct{}
+
+// CSPNonce returns the Content-Security-Policy nonce that Helmet generated for the request with the given context,
+// or an empty string if there is none. Elements carrying it in their nonce attribute are allowed by the directives
+// that ContentSecurityPolicy.AddNonce was called for.
+func CSPNonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey{}).(string)
+	return nonce
+}
+
+// AddNonce adds a per-request nonce source to the given directives, e.g. DirectiveScriptSrc and DirectiveStyleSrc.
+// Helmet generates a new nonce for every request, and makes it available to the next http.Handler through CSPNonce.
+// A directive that isn't set yet starts out with the sources it would otherwise fall back to.
+func (csp *ContentSecurityPolicy) AddNonce(directives ...CSPDirective) {
+	for _, directive := range directives {
+		if !containsCSPDirective(csp.nonces, directive) {
+			csp.nonces = append(csp.nonces, directive)
+		}
+	}
+	csp.cache = ""
+}
+
+// stringFor generates the Content-Security-Policy for the given http.Request, with its nonce if there is one.
+func (csp *ContentSecurityPolicy) stringFor(r *http.Request) string {
+	if len(csp.nonces) == 0 || r == nil {
+		return csp.String()
+	}
+
+	nonce := CSPNonce(r.Context())
+	if nonce == "" {
+		return csp.String()
+	}
+
+	withNonce := csp.Clone()
+	for _, directive := range csp.nonces {
+		sources := csp.Resolve(directive).Sources
+		withNonce.policies[directive] = normalizeCSPSources(append(sources, CSPSource("'nonce-"+nonce+"'")))
+	}
+	return withNonce.String()
+}
+
+// usesCSPNonce returns whether a Content-Security-Policy of the Helmet needs a per-request nonce.
+func (h *Helmet) usesCSPNonce() bool {
+	for _, csp := range []*ContentSecurityPolicy{h.ContentSecurityPolicy, h.ContentSecurityPolicyReportOnly} {
+		if csp != nil && len(csp.nonces) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// withCSPNonce returns the request with a new Content-Security-Policy nonce in its context.
+func withCSPNonce(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), cspNonceKey{}, newCSPNonce()))
+}
+
+// newCSPNonce generates a new random nonce, with 128 bits of entropy. It is URL-safe base64, which the nonce
+// grammar allows, so that html/template doesn't escape it.
+func newCSPNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("helmet: could not generate Content-Security-Policy nonce: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func containsCSPDirective(directives []CSPDirective, directive CSPDirective) bool {
+	for _, d := range directives {
+		if d == directive {
+			return true
+		}
+	}
+	return false
+}
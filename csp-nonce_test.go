@@ -0,0 +1,139 @@
+package helmet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContentSecurityPolicy_AddNonce(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		policies       map[CSPDirective][]CSPSource
+		directives     []CSPDirective
+		expectedHeader string // with NONCE in place of the nonce
+	}{
+		{
+			name:           "Set Directive",
+			policies:       map[CSPDirective][]CSPSource{DirectiveScriptSrc: {SourceSelf}},
+			directives:     []CSPDirective{DirectiveScriptSrc},
+			expectedHeader: "script-src 'self' 'nonce-NONCE'",
+		},
+		{
+			name:           "Fallback",
+			policies:       map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceNone}},
+			directives:     []CSPDirective{DirectiveStyleSrc},
+			expectedHeader: "default-src 'none'; style-src 'nonce-NONCE'",
+		},
+		{
+			name:           "Unrestricted",
+			policies:       map[CSPDirective][]CSPSource{},
+			directives:     []CSPDirective{DirectiveScriptSrc, DirectiveScriptSrc},
+			expectedHeader: "script-src 'nonce-NONCE'",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			helmet := Empty()
+			helmet.ContentSecurityPolicy = NewContentSecurityPolicy(tc.policies)
+			helmet.ContentSecurityPolicy.AddNonce(tc.directives...)
+
+			var nonce string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nonce = CSPNonce(r.Context())
+			})
+
+			rr, r := newRecorderRequest(t)
+			helmet.Secure(next).ServeHTTP(rr, r)
+
+			if len(nonce) < 22 {
+				t.Fatalf("The next http.Handler should get a random nonce\tActual: %s\n", nonce)
+			}
+
+			header := rr.Result().Header.Get(HeaderContentSecurityPolicy)
+			expected := strings.ReplaceAll(tc.expectedHeader, "NONCE", nonce)
+			if normalizeCSP(header) != normalizeCSP(expected) {
+				t.Errorf("Expected: %s\tActual: %s\n", expected, header)
+			}
+
+			// without a request, there is no nonce
+			if strings.Contains(helmet.ContentSecurityPolicy.String(), "nonce") {
+				t.Errorf("String() should not contain a nonce\tActual: %s\n", helmet.ContentSecurityPolicy)
+			}
+		})
+	}
+}
+
+func TestCSPNonce_withoutNonce(t *testing.T) {
+	t.Parallel()
+
+	if nonce := CSPNonce(context.Background()); nonce != "" {
+		t.Errorf("Expected no nonce\tActual: %s\n", nonce)
+	}
+
+	helmet := Default()
+	var nonce string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = CSPNonce(r.Context())
+	})
+
+	rr, r := newRecorderRequest(t)
+	helmet.Secure(next).ServeHTTP(rr, r)
+
+	if nonce != "" {
+		t.Errorf("A Helmet without nonces should not generate one\tActual: %s\n", nonce)
+	}
+}
+
+func TestContentSecurityPolicy_onlyNonces(t *testing.T) {
+	t.Parallel()
+
+	csp := EmptyContentSecurityPolicy()
+	csp.AddNonce(DirectiveScriptSrc)
+
+	rr := httptest.NewRecorder()
+	csp.Header(rr)
+	if header, ok := rr.Result().Header[HeaderContentSecurityPolicy]; ok {
+		t.Errorf("A Content-Security-Policy with only nonces should not be sent without a nonce\tActual: %s\n", header)
+	}
+
+	rr, r := newRecorderRequest(t)
+	csp.HeaderFor(rr, r)
+	if header, ok := rr.Result().Header[HeaderContentSecurityPolicy]; ok {
+		t.Errorf("A Content-Security-Policy with only nonces should not be sent for a request without a nonce\tActual: %s\n", header)
+	}
+
+	rr = httptest.NewRecorder()
+	cspReportOnly{csp}.HeaderFor(rr, r)
+	if header, ok := rr.Result().Header[HeaderContentSecurityPolicyReportOnly]; ok {
+		t.Errorf("A report-only policy with only nonces should not be sent for a request without a nonce\tActual: %s\n", header)
+	}
+
+	if tag, _ := csp.MetaTag(); tag != "" {
+		t.Errorf("A Content-Security-Policy with only nonces should not render a <meta> element\tActual: %s\n", tag)
+	}
+
+	helmet := Empty()
+	helmet.ContentSecurityPolicy = csp
+
+	var nonce string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = CSPNonce(r.Context())
+	})
+
+	rr, r = newRecorderRequest(t)
+	helmet.Secure(next).ServeHTTP(rr, r)
+
+	expected := "script-src 'nonce-" + nonce + "'"
+	if header := rr.Result().Header.Get(HeaderContentSecurityPolicy); header != expected {
+		t.Errorf("Expected: %s\tActual: %s\n", expected, header)
+	}
+}
@@ -0,0 +1,115 @@
+package helmet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CSPFetchDirectives are the Content-Security-Policy fetch directives whose sources can be resolved, sorted.
+var CSPFetchDirectives = []CSPDirective{
+	DirectiveChildSrc,
+	DirectiveConnectSrc,
+	DirectiveDefaultSrc,
+	DirectiveFontSrc,
+	DirectiveFrameSrc,
+	DirectiveImgSrc,
+	DirectiveManifestSrc,
+	DirectiveMediaSrc,
+	DirectiveObjectSrc,
+	DirectivePrefetchSrc,
+	DirectiveScriptSrc,
+	DirectiveScriptSrcAttr,
+	DirectiveScriptSrcElem,
+	DirectiveStyleSrc,
+	DirectiveStyleSrcAttr,
+	DirectiveStyleSrcElem,
+	DirectiveWorkerSrc,
+}
+
+// cspFallbacks are the directives that each fetch directive falls back to, in order, when it isn't set.
+var cspFallbacks = map[CSPDirective][]CSPDirective{
+	DirectiveScriptSrcElem: {DirectiveScriptSrc, DirectiveDefaultSrc},
+	DirectiveScriptSrcAttr: {DirectiveScriptSrc, DirectiveDefaultSrc},
+	DirectiveScriptSrc:     {DirectiveDefaultSrc},
+	DirectiveStyleSrcElem:  {DirectiveStyleSrc, DirectiveDefaultSrc},
+	DirectiveStyleSrcAttr:  {DirectiveStyleSrc, DirectiveDefaultSrc},
+	DirectiveStyleSrc:      {DirectiveDefaultSrc},
+	DirectiveWorkerSrc:     {DirectiveChildSrc, DirectiveScriptSrc, DirectiveDefaultSrc},
+	DirectiveFrameSrc:      {DirectiveChildSrc, DirectiveDefaultSrc},
+	DirectiveChildSrc:      {DirectiveDefaultSrc},
+	DirectiveConnectSrc:    {DirectiveDefaultSrc},
+	DirectiveFontSrc:       {DirectiveDefaultSrc},
+	DirectiveImgSrc:        {DirectiveDefaultSrc},
+	DirectiveManifestSrc:   {DirectiveDefaultSrc},
+	DirectiveMediaSrc:      {DirectiveDefaultSrc},
+	DirectiveObjectSrc:     {DirectiveDefaultSrc},
+	DirectivePrefetchSrc:   {DirectiveDefaultSrc},
+}
+
+// CSPFallbackChain returns the given directive followed by every directive it falls back to, in order.
+// Directives that don't fall back, such as form-action, only return themselves.
+func CSPFallbackChain(directive CSPDirective) []CSPDirective {
+	return append([]CSPDirective{directive}, cspFallbacks[directive]...)
+}
+
+// CSPResolution represents what a Content-Security-Policy directive resolves to after falling back to other directives.
+type CSPResolution struct {
+	Directive CSPDirective   // the directive that was resolved
+	Effective CSPDirective   // the directive whose sources apply, empty if the policy doesn't restrict the directive
+	Sources   []CSPSource    // the sources of the Effective directive
+	Chain     []CSPDirective // the directives consulted, in order, ending with the Effective one when there is one
+}
+
+// Restricted returns whether the Content-Security-Policy restricts the resolved directive at all.
+func (r CSPResolution) Restricted() bool {
+	return r.Effective != ""
+}
+
+// String explains the resolution, e.g. "worker-src -> child-src -> script-src: 'self'".
+func (r CSPResolution) String() string {
+	chain := []string{}
+	for _, directive := range r.Chain {
+		chain = append(chain, string(directive))
+	}
+
+	if !r.Restricted() {
+		return fmt.Sprintf("%s: unrestricted", strings.Join(chain, " -> "))
+	}
+
+	if len(r.Sources) == 0 {
+		return fmt.Sprintf("%s: %s", strings.Join(chain, " -> "), SourceNone)
+	}
+
+	sources := []string{}
+	for _, source := range r.Sources {
+		sources = append(sources, string(source))
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(chain, " -> "), strings.Join(sources, " "))
+}
+
+// Resolve returns the sources that apply to the given directive, after falling back to other directives
+// as CSP Level 3 does, along with the chain of directives that were consulted.
+func (csp *ContentSecurityPolicy) Resolve(directive CSPDirective) CSPResolution {
+	resolution := CSPResolution{Directive: directive, Chain: []CSPDirective{}}
+
+	for _, candidate := range CSPFallbackChain(directive) {
+		resolution.Chain = append(resolution.Chain, candidate)
+
+		if sources, ok := csp.policies[candidate]; ok {
+			resolution.Effective = candidate
+			resolution.Sources = append([]CSPSource{}, sources...)
+			break
+		}
+	}
+
+	return resolution
+}
+
+// ResolveAll resolves every fetch directive, in the order of CSPFetchDirectives.
+func (csp *ContentSecurityPolicy) ResolveAll() []CSPResolution {
+	resolutions := []CSPResolution{}
+	for _, directive := range CSPFetchDirectives {
+		resolutions = append(resolutions, csp.Resolve(directive))
+	}
+	return resolutions
+}
@@ -0,0 +1,142 @@
+package helmet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCSPFallbackChain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		directive     CSPDirective
+		expectedChain []CSPDirective
+	}{
+		{name: "Worker Src", directive: DirectiveWorkerSrc, expectedChain: []CSPDirective{DirectiveWorkerSrc, DirectiveChildSrc, DirectiveScriptSrc, DirectiveDefaultSrc}},
+		{name: "Script Src Elem", directive: DirectiveScriptSrcElem, expectedChain: []CSPDirective{DirectiveScriptSrcElem, DirectiveScriptSrc, DirectiveDefaultSrc}},
+		{name: "Frame Src", directive: DirectiveFrameSrc, expectedChain: []CSPDirective{DirectiveFrameSrc, DirectiveChildSrc, DirectiveDefaultSrc}},
+		{name: "Default Src", directive: DirectiveDefaultSrc, expectedChain: []CSPDirective{DirectiveDefaultSrc}},
+		{name: "Form Action", directive: DirectiveFormAction, expectedChain: []CSPDirective{DirectiveFormAction}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			chain := CSPFallbackChain(tc.directive)
+			if !reflect.DeepEqual(chain, tc.expectedChain) {
+				t.Errorf("Expected: %v\tActual: %v\n", tc.expectedChain, chain)
+			}
+		})
+	}
+}
+
+func TestContentSecurityPolicy_Resolve(t *testing.T) {
+	t.Parallel()
+
+	csp := NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
+		DirectiveDefaultSrc: {SourceSelf},
+		DirectiveScriptSrc:  {SourceSelf, "https://cdn.example.com"},
+		DirectiveChildSrc:   {},
+	})
+
+	testCases := []struct {
+		name               string
+		directive          CSPDirective
+		expectedResolution CSPResolution
+		expectedString     string
+	}{
+		{
+			name:      "Set",
+			directive: DirectiveScriptSrc,
+			expectedResolution: CSPResolution{
+				Directive: DirectiveScriptSrc,
+				Effective: DirectiveScriptSrc,
+				Sources:   []CSPSource{SourceSelf, "https://cdn.example.com"},
+				Chain:     []CSPDirective{DirectiveScriptSrc},
+			},
+			expectedString: "script-src: 'self' https://cdn.example.com",
+		},
+		{
+			name:      "Fallback",
+			directive: DirectiveScriptSrcElem,
+			expectedResolution: CSPResolution{
+				Directive: DirectiveScriptSrcElem,
+				Effective: DirectiveScriptSrc,
+				Sources:   []CSPSource{SourceSelf, "https://cdn.example.com"},
+				Chain:     []CSPDirective{DirectiveScriptSrcElem, DirectiveScriptSrc},
+			},
+			expectedString: "script-src-elem -> script-src: 'self' https://cdn.example.com",
+		},
+		{
+			name:      "Fallback To Empty",
+			directive: DirectiveWorkerSrc,
+			expectedResolution: CSPResolution{
+				Directive: DirectiveWorkerSrc,
+				Effective: DirectiveChildSrc,
+				Sources:   []CSPSource{},
+				Chain:     []CSPDirective{DirectiveWorkerSrc, DirectiveChildSrc},
+			},
+			expectedString: "worker-src -> child-src: 'none'",
+		},
+		{
+			name:      "Default",
+			directive: DirectiveImgSrc,
+			expectedResolution: CSPResolution{
+				Directive: DirectiveImgSrc,
+				Effective: DirectiveDefaultSrc,
+				Sources:   []CSPSource{SourceSelf},
+				Chain:     []CSPDirective{DirectiveImgSrc, DirectiveDefaultSrc},
+			},
+			expectedString: "img-src -> default-src: 'self'",
+		},
+		{
+			name:      "Unrestricted",
+			directive: DirectiveFormAction,
+			expectedResolution: CSPResolution{
+				Directive: DirectiveFormAction,
+				Chain:     []CSPDirective{DirectiveFormAction},
+			},
+			expectedString: "form-action: unrestricted",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolution := csp.Resolve(tc.directive)
+			if !reflect.DeepEqual(resolution, tc.expectedResolution) {
+				t.Errorf("Expected: %+v\tActual: %+v\n", tc.expectedResolution, resolution)
+			}
+
+			if resolution.String() != tc.expectedString {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedString, resolution)
+			}
+		})
+	}
+}
+
+func TestContentSecurityPolicy_ResolveAll(t *testing.T) {
+	t.Parallel()
+
+	csp := NewContentSecurityPolicy(map[CSPDirective][]CSPSource{DirectiveDefaultSrc: {SourceSelf}})
+
+	resolutions := csp.ResolveAll()
+	if len(resolutions) != len(CSPFetchDirectives) {
+		t.Fatalf("Expected: %d resolutions\tActual: %d\n", len(CSPFetchDirectives), len(resolutions))
+	}
+
+	for i, resolution := range resolutions {
+		if resolution.Directive != CSPFetchDirectives[i] {
+			t.Errorf("Expected: %s\tActual: %s\n", CSPFetchDirectives[i], resolution.Directive)
+		}
+
+		if resolution.Effective != DirectiveDefaultSrc {
+			t.Errorf("%s should resolve to default-src\tActual: %s\n", resolution.Directive, resolution.Effective)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package csptemplate integrates the per-request Content-Security-Policy nonce that Helmet generates with html/template.
+//
+// Register the template functions before parsing, then execute the templates through Execute or ExecuteTemplate:
+//
+//	t := template.Must(template.New("page").Funcs(csptemplate.FuncMap()).Parse(page))
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		if err := csptemplate.Execute(t, w, r, data); err != nil {
+//			...
+//		}
+//	}
+//
+// Templates can use the nonce explicitly, e.g. <script nonce="{{ cspNonce }}"> or {{ cspScript "/app.js" }},
+// but Execute also adds it to every <script> and <style> tag that doesn't have one yet. That includes the tags of
+// template.HTML values, so only values that are trusted as much as the template itself may be template.HTML.
+package csptemplate
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goddtriffin/helmet"
+	"github.com/goddtriffin/helmet/internal/htmlnonce"
+)
+
+// placeholder stands in for the nonce in the output of the template functions, until Execute replaces it with
+// the nonce of the request. It is random, so that template data can't forge it, and alphanumeric, so that html/template
+// never escapes it. Its first byte appears only once in it, so a partial placeholder is never part of a whole one.
+var placeholder = newPlaceholder()
+
+func newPlaceholder() []byte {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("csptemplate: could not generate nonce placeholder: " + err.Error())
+	}
+	return []byte(fmt.Sprintf("Zcspnonce%x", b))
+}
+
+// FuncMap returns the template functions, which must be registered before the templates are parsed:
+//
+//   - cspNonce returns the nonce of the request
+//   - cspScript returns a <script> element that loads the given URL, with the nonce of the request
+//
+// They output a placeholder, which only Execute and ExecuteTemplate replace with the nonce of the request,
+// so the template functions don't change between requests, and templates are executed without being cloned.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"cspNonce": func() string {
+			return string(placeholder)
+		},
+		"cspScript": func(src string) template.HTML {
+			return template.HTML(fmt.Sprintf(
+				`<script src="%s" nonce="%s"></script>`,
+				template.HTMLEscapeString(safeURL(src)),
+				placeholder,
+			))
+		},
+	}
+}
+
+// safeURL returns the URL, unless its scheme could execute code, like html/template does for URL attributes.
+func safeURL(src string) string {
+	u, err := url.Parse(strings.TrimSpace(src))
+	if err != nil {
+		return "#ZgotmplZ"
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return src
+	default:
+		return "#ZgotmplZ"
+	}
+}
+
+// Execute applies the template to the data object for the given http.Request, and writes the output to w.
+// The template functions output the nonce of the request, and every <script> and <style> tag in the output
+// gets a nonce attribute, unless it already has one.
+func Execute(t *template.Template, w io.Writer, r *http.Request, data interface{}) error {
+	return execute(w, r, func(w io.Writer) error {
+		return t.Execute(w, data)
+	})
+}
+
+// ExecuteTemplate is like Execute, but applies the template associated with t that has the given name.
+func ExecuteTemplate(t *template.Template, w io.Writer, r *http.Request, name string, data interface{}) error {
+	return execute(w, r, func(w io.Writer) error {
+		return t.ExecuteTemplate(w, name, data)
+	})
+}
+
+func execute(w io.Writer, r *http.Request, exec func(io.Writer) error) error {
+	nonce := helmet.CSPNonce(r.Context())
+	if nonce != "" {
+		w = htmlnonce.NewWriter(w, nonce)
+	}
+
+	nw := &nonceWriter{w: w, nonce: []byte(nonce)}
+	if err := exec(nw); err != nil {
+		return err
+	}
+	return nw.flush()
+}
+
+// nonceWriter replaces the placeholder with the nonce. It holds back the end of every write that could be
+// the start of a placeholder, until the next write or flush.
+type nonceWriter struct {
+	w       io.Writer
+	nonce   []byte
+	pending []byte
+}
+
+func (nw *nonceWriter) Write(p []byte) (int, error) {
+	nw.pending = append(nw.pending, p...)
+
+	held := 0
+	for n := len(placeholder) - 1; n > 0; n-- {
+		if bytes.HasSuffix(nw.pending, placeholder[:n]) {
+			held = n
+			break
+		}
+	}
+
+	out := bytes.ReplaceAll(nw.pending[:len(nw.pending)-held], placeholder, nw.nonce)
+	nw.pending = append(nw.pending[:0], nw.pending[len(nw.pending)-held:]...)
+	if _, err := nw.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush writes what was held back.
+func (nw *nonceWriter) flush() error {
+	if len(nw.pending) == 0 {
+		return nil
+	}
+
+	_, err := nw.w.Write(nw.pending)
+	nw.pending = nil
+	return err
+}
@@ -0,0 +1,201 @@
+package csptemplate
+
+import (
+	"crypto/tls"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goddtriffin/helmet"
+)
+
+const page = `{{ define "page" }}<script>a()</script><script nonce="{{ cspNonce }}">b()</script>{{ cspScript .Src }}<style>p{}</style>{{ end }}`
+
+// serve executes the page template through a Helmet that adds a nonce to script-src and style-src,
+// and returns the nonce from the Content-Security-Policy along with the body.
+func serve(t *testing.T, exec func(w http.ResponseWriter, r *http.Request) error) (string, string) {
+	t.Helper()
+
+	h := helmet.Empty()
+	h.ContentSecurityPolicy.Add(helmet.DirectiveDefaultSrc, helmet.SourceSelf)
+	h.ContentSecurityPolicy.AddNonce(helmet.DirectiveScriptSrc, helmet.DirectiveStyleSrc)
+
+	handler := h.Secure(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := exec(w, r); err != nil {
+			t.Errorf("Unexpected error: %v\n", err)
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+	r.TLS = &tls.ConnectionState{}
+	handler.ServeHTTP(rr, r)
+
+	csp := rr.Result().Header.Get(helmet.HeaderContentSecurityPolicy)
+	i := strings.Index(csp, "'nonce-")
+	if i < 0 {
+		t.Fatalf("Content-Security-Policy has no nonce\tActual: %s\n", csp)
+	}
+	nonce := csp[i+len("'nonce-"):]
+	nonce = nonce[:strings.Index(nonce, "'")]
+
+	return nonce, rr.Body.String()
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("root").Funcs(FuncMap()).Parse(page))
+	data := struct{ Src string }{"/app.js"}
+
+	nonce, body := serve(t, func(w http.ResponseWriter, r *http.Request) error {
+		return ExecuteTemplate(tmpl, w, r, "page", data)
+	})
+
+	expected := `<script nonce="` + nonce + `">a()</script>` +
+		`<script nonce="` + nonce + `">b()</script>` +
+		`<script src="/app.js" nonce="` + nonce + `"></script>` +
+		`<style nonce="` + nonce + `">p{}</style>`
+	if body != expected {
+		t.Errorf("Expected: %s\tActual: %s\n", expected, body)
+	}
+
+	// every request gets its own nonce
+	otherNonce, _ := serve(t, func(w http.ResponseWriter, r *http.Request) error {
+		return ExecuteTemplate(tmpl, w, r, "page", data)
+	})
+	if otherNonce == nonce {
+		t.Errorf("Nonces should not be reused\tActual: %s\n", nonce)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("root").Funcs(FuncMap()).Parse(`<style>p{color:{{ . }}}</style>`))
+
+	nonce, body := serve(t, func(w http.ResponseWriter, r *http.Request) error {
+		return Execute(tmpl, w, r, "red")
+	})
+
+	expected := `<style nonce="` + nonce + `">p{color:red}</style>`
+	if body != expected {
+		t.Errorf("Expected: %s\tActual: %s\n", expected, body)
+	}
+}
+
+func TestExecute_withoutNonce(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("root").Funcs(FuncMap()).Parse(`<script nonce="{{ cspNonce }}"></script>`))
+
+	var body strings.Builder
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := Execute(tmpl, &body, r, nil); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	expected := `<script nonce=""></script>`
+	if body.String() != expected {
+		t.Errorf("Expected: %s\tActual: %s\n", expected, body.String())
+	}
+}
+
+func TestExecute_executedDirectly(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("root").Funcs(FuncMap()).Parse(`<script nonce="{{ cspNonce }}"></script>`))
+
+	// executing the template directly doesn't keep Execute from working, unlike a template that has to be cloned
+	if err := tmpl.Execute(io.Discard, nil); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	nonce, body := serve(t, func(w http.ResponseWriter, r *http.Request) error {
+		return Execute(tmpl, w, r, nil)
+	})
+
+	expected := `<script nonce="` + nonce + `"></script>`
+	if body != expected {
+		t.Errorf("Expected: %s\tActual: %s\n", expected, body)
+	}
+}
+
+func TestNonceWriter(t *testing.T) {
+	t.Parallel()
+
+	output := "<p>" + string(placeholder) + "</p>" + string(placeholder[:4]) + "<p>" + string(placeholder)
+
+	testCases := []struct {
+		name      string
+		chunkSize int
+	}{
+		{name: "One Write", chunkSize: len(output)},
+		{name: "Byte By Byte", chunkSize: 1},
+		{name: "Split Placeholders", chunkSize: 7},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var body strings.Builder
+			nw := &nonceWriter{w: &body, nonce: []byte("abc")}
+			for i := 0; i < len(output); i += tc.chunkSize {
+				end := i + tc.chunkSize
+				if end > len(output) {
+					end = len(output)
+				}
+				if _, err := nw.Write([]byte(output[i:end])); err != nil {
+					t.Fatalf("Unexpected error: %v\n", err)
+				}
+			}
+			if err := nw.flush(); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+
+			expected := "<p>abc</p>" + string(placeholder[:4]) + "<p>abc"
+			if body.String() != expected {
+				t.Errorf("Expected: %s\tActual: %s\n", expected, body.String())
+			}
+		})
+	}
+}
+
+func TestFuncMap_cspScript(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{name: "Relative", src: "/app.js", expected: `<script src="/app.js" nonce=""></script>`},
+		{name: "HTTPS", src: "https://cdn.example.com/app.js?a=1&b=2", expected: `<script src="https://cdn.example.com/app.js?a=1&amp;b=2" nonce=""></script>`},
+		{name: "Quotes", src: `/app.js"><b>`, expected: `<script src="/app.js&#34;&gt;&lt;b&gt;" nonce=""></script>`},
+		{name: "JavaScript", src: "javascript:alert(1)", expected: `<script src="#ZgotmplZ" nonce=""></script>`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl := template.Must(template.New("root").Funcs(FuncMap()).Parse(`{{ cspScript . }}`))
+
+			var body strings.Builder
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if err := Execute(tmpl, &body, r, tc.src); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+
+			if body.String() != tc.expected {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expected, body.String())
+			}
+		})
+	}
+}
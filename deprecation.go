@@ -0,0 +1,162 @@
+package helmet
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Deprecation describes why browsers no longer support an HTTP security header or directive.
+type Deprecation struct {
+	Since      string // when browsers stopped supporting it
+	ReplacedBy string // what to use instead, empty if nothing is needed anymore
+}
+
+func (d Deprecation) String() string {
+	if d.ReplacedBy == "" {
+		return fmt.Sprintf("deprecated since %s", d.Since)
+	}
+	return fmt.Sprintf("deprecated since %s, use %s instead", d.Since, d.ReplacedBy)
+}
+
+// DeprecatedModule is implemented by Modules that can be deprecated.
+type DeprecatedModule interface {
+	// Deprecation returns why the Module is deprecated, or nil if its current configuration is still supported.
+	Deprecation() *Deprecation
+}
+
+// cspDirectiveDeprecations are the deprecated Content-Security-Policy directives.
+var cspDirectiveDeprecations = map[CSPDirective]Deprecation{
+	DeprecatedDirectiveBlockAllMixedContent: {Since: "Mixed Content Level 2", ReplacedBy: string(DirectiveUpgradeInsecureRequests)},
+	DeprecatedDirectivePluginTypes:          {Since: "CSP Level 3", ReplacedBy: "object-src 'none'"},
+	DeprecatedDirectiveReferrer:             {Since: "CSP Level 2", ReplacedBy: HeaderReferrerPolicy},
+}
+
+// CSPDirectiveDeprecation returns why the given Content-Security-Policy directive is deprecated, or nil if it isn't.
+func CSPDirectiveDeprecation(directive CSPDirective) *Deprecation {
+	if deprecation, ok := cspDirectiveDeprecations[directive]; ok {
+		return &deprecation
+	}
+	return nil
+}
+
+// WithDeprecationWarnings logs a warning for every deprecated Module or Content-Security-Policy directive that is configured,
+// once the Helmet is constructed, and again when Secure is called for those configured since. A nil logger uses log.Default().
+func WithDeprecationWarnings(logger *log.Logger) Option {
+	return func(h *Helmet) {
+		if logger == nil {
+			logger = log.Default()
+		}
+		h.deprecationLogger = logger
+	}
+}
+
+// WithoutDeprecated strips every deprecated Module or Content-Security-Policy directive that is configured,
+// once the Helmet is constructed, and again when Secure is called.
+func WithoutDeprecated() Option {
+	return func(h *Helmet) {
+		h.stripDeprecated = true
+	}
+}
+
+// deprecationWarnings returns a Warning for every deprecated Module or Content-Security-Policy directive that is configured.
+func (h *Helmet) deprecationWarnings() []Warning {
+	warnings := []Warning{}
+
+	for _, module := range h.All() {
+		if deprecation := moduleDeprecation(module); deprecation != nil {
+			warnings = append(warnings, Warning{module.Name(), deprecation.String()})
+		}
+	}
+
+	warnings = append(warnings, h.cspDeprecationWarnings(HeaderContentSecurityPolicy, h.ContentSecurityPolicy)...)
+	warnings = append(warnings, h.cspDeprecationWarnings(HeaderContentSecurityPolicyReportOnly, h.ContentSecurityPolicyReportOnly)...)
+	return warnings
+}
+
+// cspDeprecationWarnings returns a Warning for every deprecated directive of the Content-Security-Policy sent as the given header.
+func (h *Helmet) cspDeprecationWarnings(header string, csp *ContentSecurityPolicy) []Warning {
+	warnings := []Warning{}
+	if csp == nil || h.disabled[strings.ToLower(header)] {
+		return warnings
+	}
+
+	for _, directive := range csp.DeprecatedDirectives() {
+		warnings = append(warnings, Warning{
+			header,
+			fmt.Sprintf("%s directive is %s", directive, CSPDirectiveDeprecation(directive)),
+		})
+	}
+	return warnings
+}
+
+// handleDeprecated logs and strips what is deprecated, as the options configured. It runs once the Helmet is constructed,
+// and again when Secure is called; a warning that was already logged isn't logged again.
+func (h *Helmet) handleDeprecated() {
+	if h.deprecationLogger != nil {
+		for _, warning := range h.deprecationWarnings() {
+			if h.loggedDeprecations[warning.String()] {
+				continue
+			}
+
+			if h.loggedDeprecations == nil {
+				h.loggedDeprecations = map[string]bool{}
+			}
+			h.loggedDeprecations[warning.String()] = true
+			h.deprecationLogger.Printf("helmet: %s", warning)
+		}
+	}
+	if h.stripDeprecated {
+		h.removeDeprecated()
+	}
+}
+
+// removeDeprecated disables every deprecated Module and removes every deprecated Content-Security-Policy directive.
+func (h *Helmet) removeDeprecated() {
+	for _, module := range h.All() {
+		if moduleDeprecation(module) != nil {
+			h.Disable(module.Name())
+		}
+	}
+
+	h.ContentSecurityPolicy = withoutDeprecatedDirectives(h.ContentSecurityPolicy)
+	h.ContentSecurityPolicyReportOnly = withoutDeprecatedDirectives(h.ContentSecurityPolicyReportOnly)
+}
+
+// withoutDeprecatedDirectives returns the Content-Security-Policy without its deprecated directives. It removes them
+// from a copy, since the Content-Security-Policy may be shared with other Helmets.
+func withoutDeprecatedDirectives(csp *ContentSecurityPolicy) *ContentSecurityPolicy {
+	if csp == nil || len(csp.DeprecatedDirectives()) == 0 {
+		return csp
+	}
+
+	clone := csp.Clone()
+	clone.Remove(clone.DeprecatedDirectives()...)
+	return clone
+}
+
+// moduleDeprecation returns why the given configured Module is deprecated, or nil if it isn't.
+func moduleDeprecation(module Module) *Deprecation {
+	if module.Empty() {
+		return nil
+	}
+
+	if deprecated, ok := module.(DeprecatedModule); ok {
+		return deprecated.Deprecation()
+	}
+	return nil
+}
+
+// DeprecatedDirectives returns the deprecated directives of the Content-Security-Policy, sorted.
+func (csp *ContentSecurityPolicy) DeprecatedDirectives() []CSPDirective {
+	directives := []CSPDirective{}
+	for directive := range csp.policies {
+		if CSPDirectiveDeprecation(directive) != nil {
+			directives = append(directives, directive)
+		}
+	}
+
+	sort.Slice(directives, func(i, j int) bool { return directives[i] < directives[j] })
+	return directives
+}
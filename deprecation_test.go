@@ -0,0 +1,237 @@
+package helmet
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestDeprecation_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		deprecation    Deprecation
+		expectedString string
+	}{
+		{name: "Not Replaced", deprecation: Deprecation{Since: "Chrome 107"}, expectedString: "deprecated since Chrome 107"},
+		{
+			name:           "Replaced",
+			deprecation:    Deprecation{Since: "Chrome 88", ReplacedBy: "Permissions-Policy"},
+			expectedString: "deprecated since Chrome 88, use Permissions-Policy instead",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			str := tc.deprecation.String()
+			if str != tc.expectedString {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedString, str)
+			}
+		})
+	}
+}
+
+func TestCSPDirectiveDeprecation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		directive          CSPDirective
+		expectedDeprecated bool
+	}{
+		{name: "Referrer", directive: DeprecatedDirectiveReferrer, expectedDeprecated: true},
+		{name: "Plugin Types", directive: DeprecatedDirectivePluginTypes, expectedDeprecated: true},
+		{name: "Block All Mixed Content", directive: DeprecatedDirectiveBlockAllMixedContent, expectedDeprecated: true},
+		{name: "Default Src", directive: DirectiveDefaultSrc, expectedDeprecated: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			deprecated := CSPDirectiveDeprecation(tc.directive) != nil
+			if deprecated != tc.expectedDeprecated {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedDeprecated, deprecated)
+			}
+		})
+	}
+}
+
+func TestHelmet_deprecationWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		configure        func(h *Helmet)
+		expectedWarnings []string // substrings, one per Warning, in order
+	}{
+		{name: "Default", configure: func(h *Helmet) {}, expectedWarnings: []string{}},
+		{
+			name: "Expect-CT",
+			configure: func(h *Helmet) {
+				h.ExpectCT = NewExpectCT(30, true, "")
+			},
+			expectedWarnings: []string{"Expect-CT: deprecated since"},
+		},
+		{
+			name: "Feature-Policy",
+			configure: func(h *Helmet) {
+				h.FeaturePolicy = NewFeaturePolicy(map[FeaturePolicyDirective][]FeaturePolicyOrigin{DirectiveCamera: {OriginSelf}})
+			},
+			expectedWarnings: []string{"use Permissions-Policy instead"},
+		},
+		{
+			name: "X-XSS-Protection Block",
+			configure: func(h *Helmet) {
+				h.XXSSProtection = BlockXXSSProtection()
+			},
+			expectedWarnings: []string{"X-XSS-Protection: deprecated since"},
+		},
+		{
+			name: "Disabled Module",
+			configure: func(h *Helmet) {
+				h.ExpectCT = NewExpectCT(30, true, "")
+				h.Disable(HeaderExpectCT)
+			},
+			expectedWarnings: []string{},
+		},
+		{
+			name: "CSP Directives",
+			configure: func(h *Helmet) {
+				h.ContentSecurityPolicy.Add(DirectiveDefaultSrc, SourceSelf)
+				h.ContentSecurityPolicy.Add(DeprecatedDirectiveReferrer, DeprecatedReferrerNone)
+				h.ContentSecurityPolicy.Add(DeprecatedDirectivePluginTypes, "application/pdf")
+			},
+			expectedWarnings: []string{"plugin-types directive", "referrer directive is deprecated since CSP Level 2, use Referrer-Policy"},
+		},
+		{
+			name: "CSP Report Only Directives",
+			configure: func(h *Helmet) {
+				h.ContentSecurityPolicyReportOnly.Add(DeprecatedDirectiveBlockAllMixedContent)
+			},
+			expectedWarnings: []string{"Content-Security-Policy-Report-Only: block-all-mixed-content directive is deprecated"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			helmet := Default()
+			tc.configure(helmet)
+
+			warnings := helmet.deprecationWarnings()
+			if len(warnings) != len(tc.expectedWarnings) {
+				t.Fatalf("Incorrect warnings\tExpected: %v\tActual: %v\n", tc.expectedWarnings, warnings)
+			}
+
+			for i, warning := range warnings {
+				if !strings.Contains(warning.String(), tc.expectedWarnings[i]) {
+					t.Errorf("Incorrect warning\tExpected: %s\tActual: %s\n", tc.expectedWarnings[i], warning)
+				}
+			}
+		})
+	}
+}
+
+func TestHelmet_WithDeprecationWarnings(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	helmet := Default(WithLegacyXXSSProtection(), WithDeprecationWarnings(log.New(&logs, "", 0)))
+
+	// warnings are logged at construction time
+	if !strings.Contains(logs.String(), "helmet: X-XSS-Protection: deprecated since") {
+		t.Errorf("Expected a deprecation warning at construction time\tActual: %s\n", logs.String())
+	}
+
+	helmet.ExpectCT = NewExpectCT(30, true, "")
+
+	rr, r := newRecorderRequest(t)
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+
+	if !strings.Contains(logs.String(), "helmet: Expect-CT: deprecated since") {
+		t.Errorf("Expected a deprecation warning\tActual: %s\n", logs.String())
+	}
+
+	// and only once
+	if count := strings.Count(logs.String(), "X-XSS-Protection"); count != 1 {
+		t.Errorf("Expected one X-XSS-Protection warning\tActual: %s\n", logs.String())
+	}
+
+	// warnings don't strip anything
+	if header := rr.Result().Header.Get(HeaderExpectCT); header == "" {
+		t.Errorf("Expect-CT should still be set\n")
+	}
+}
+
+func TestHelmet_WithoutDeprecated(t *testing.T) {
+	t.Parallel()
+
+	helmet := Default(WithoutDeprecated())
+	helmet.ExpectCT = NewExpectCT(30, true, "")
+	helmet.XXSSProtection = BlockXXSSProtection()
+	helmet.ContentSecurityPolicy.Add(DirectiveDefaultSrc, SourceSelf)
+	helmet.ContentSecurityPolicy.Add(DeprecatedDirectiveReferrer, DeprecatedReferrerNone)
+
+	rr, r := newRecorderRequest(t)
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+	resp := rr.Result()
+
+	testCases := []struct {
+		name   string
+		header string
+	}{
+		{HeaderExpectCT, ""},
+		{HeaderXXSSProtection, ""},
+		{HeaderContentSecurityPolicy, "default-src 'self'"},
+		{HeaderXFrameOptions, XFrameOptionsSameOrigin.String()},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := resp.Header.Get(tc.name)
+			if header != tc.header {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.header, header)
+			}
+		})
+	}
+}
+
+func TestHelmet_WithoutDeprecated_sharedCSP(t *testing.T) {
+	t.Parallel()
+
+	csp := NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
+		DirectiveDefaultSrc:                     {SourceSelf},
+		DeprecatedDirectiveBlockAllMixedContent: {},
+	})
+
+	stripped := Default(WithoutDeprecated())
+	stripped.ContentSecurityPolicy = csp
+	stripped.ContentSecurityPolicyReportOnly = csp
+	stripped.Secure(mockNext)
+
+	for header, strippedCSP := range map[string]*ContentSecurityPolicy{
+		HeaderContentSecurityPolicy:           stripped.ContentSecurityPolicy,
+		HeaderContentSecurityPolicyReportOnly: stripped.ContentSecurityPolicyReportOnly,
+	} {
+		if str := strippedCSP.String(); str != "default-src 'self'" {
+			t.Errorf("%s should be stripped\tExpected: default-src 'self'\tActual: %s\n", header, str)
+		}
+	}
+
+	// a Helmet sharing the Content-Security-Policy still sends it as configured
+	if _, ok := csp.policies[DeprecatedDirectiveBlockAllMixedContent]; !ok {
+		t.Errorf("Shared Content-Security-Policy should be unchanged\tActual: %s\n", csp)
+	}
+}
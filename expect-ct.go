@@ -89,9 +89,20 @@ func (ect *ExpectCT) Empty() bool {
 	return ect.MaxAge == 0
 }
 
-// Header adds the Expect-CT HTTP security header to the given http.ResponseWriter.
-func (ect *ExpectCT) Header(w http.ResponseWriter) {
+// Deprecation returns why the Expect-CT is deprecated: browsers enforce Certificate Transparency by default.
+func (ect *ExpectCT) Deprecation() *Deprecation {
+	return &Deprecation{Since: "Chrome 107"}
+}
+
+// HeaderFor adds the Expect-CT HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (ect *ExpectCT) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !ect.Empty() {
 		w.Header().Set(HeaderExpectCT, ect.String())
 	}
 }
+
+// Header adds the Expect-CT HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (ect *ExpectCT) Header(w http.ResponseWriter) {
+	ect.HeaderFor(w, nil)
+}
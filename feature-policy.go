@@ -154,9 +154,20 @@ func (fp *FeaturePolicy) Empty() bool {
 	return len(fp.policies) == 0
 }
 
-// Header adds the Feature-Policy HTTP security header to the given http.ResponseWriter.
-func (fp *FeaturePolicy) Header(w http.ResponseWriter) {
+// Deprecation returns why the Feature-Policy is deprecated: it was renamed to Permissions-Policy.
+func (fp *FeaturePolicy) Deprecation() *Deprecation {
+	return &Deprecation{Since: "Chrome 88", ReplacedBy: "Permissions-Policy"}
+}
+
+// HeaderFor adds the Feature-Policy HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (fp *FeaturePolicy) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !fp.Empty() {
 		w.Header().Set(HeaderFeaturePolicy, fp.String())
 	}
 }
+
+// Header adds the Feature-Policy HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (fp *FeaturePolicy) Header(w http.ResponseWriter) {
+	fp.HeaderFor(w, nil)
+}
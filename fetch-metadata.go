@@ -0,0 +1,131 @@
+package helmet
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// List of all Fetch Metadata HTTP request headers.
+const (
+	HeaderSecFetchSite = "Sec-Fetch-Site"
+	HeaderSecFetchMode = "Sec-Fetch-Mode"
+	HeaderSecFetchDest = "Sec-Fetch-Dest"
+)
+
+// ModuleFetchMetadata is the name of the FetchMetadata Module.
+const ModuleFetchMetadata = "Fetch-Metadata"
+
+// FetchMetadata rejects cross-site requests using the Fetch Metadata HTTP request headers,
+// implementing the resource isolation policy (https://web.dev/fetch-metadata/).
+// Same-origin, same-site and user-initiated requests are allowed, as are cross-site top-level GET navigations.
+// Requests from browsers that don't send Fetch Metadata are always allowed.
+type FetchMetadata struct {
+	// Paths that are meant to be used cross-site, such as public APIs, and are never rejected.
+	// A path ending in a slash matches every path below it, otherwise the path must match exactly.
+	ExemptPaths []string
+
+	// Whether to only log requests that would be rejected, instead of rejecting them.
+	ReportOnly bool
+
+	// Where rejected requests are logged in report-only mode. Defaults to the standard logger.
+	Logger *log.Logger
+}
+
+// NewFetchMetadata creates a new FetchMetadata.
+func NewFetchMetadata(exemptPaths ...string) *FetchMetadata {
+	return &FetchMetadata{ExemptPaths: exemptPaths}
+}
+
+// Name returns the name of the FetchMetadata Module.
+func (fm *FetchMetadata) Name() string {
+	return ModuleFetchMetadata
+}
+
+// Empty returns whether the FetchMetadata is empty.
+func (fm *FetchMetadata) Empty() bool {
+	// the resource isolation policy needs no configuration
+	return false
+}
+
+// Header does nothing, since FetchMetadata only inspects requests.
+func (fm *FetchMetadata) Header(w http.ResponseWriter, r *http.Request) {}
+
+// Allow returns whether the http.Request passes the resource isolation policy, rejecting it with 403 Forbidden if not.
+func (fm *FetchMetadata) Allow(w http.ResponseWriter, r *http.Request) bool {
+	if fm.allowed(r) {
+		return true
+	}
+
+	if fm.ReportOnly {
+		logger := fm.Logger
+		if logger == nil {
+			logger = log.Default()
+		}
+
+		logger.Printf(
+			"helmet: fetch metadata would reject %s %s (%s=%s, %s=%s, %s=%s)",
+			r.Method, r.URL.Path,
+			HeaderSecFetchSite, r.Header.Get(HeaderSecFetchSite),
+			HeaderSecFetchMode, r.Header.Get(HeaderSecFetchMode),
+			HeaderSecFetchDest, r.Header.Get(HeaderSecFetchDest),
+		)
+		return true
+	}
+
+	http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	return false
+}
+
+// Secure is the middleware handler, for using FetchMetadata without Helmet.
+func (fm *FetchMetadata) Secure(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fm.Allow(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (fm *FetchMetadata) allowed(r *http.Request) bool {
+	site := r.Header.Get(HeaderSecFetchSite)
+
+	// browsers without Fetch Metadata support
+	if site == "" {
+		return true
+	}
+
+	if site == "same-origin" || site == "same-site" || site == "none" {
+		return true
+	}
+
+	if fm.exempt(r.URL.Path) {
+		return true
+	}
+
+	// cross-site top-level navigations, which can't be embedded
+	dest := r.Header.Get(HeaderSecFetchDest)
+	if r.Header.Get(HeaderSecFetchMode) == "navigate" && r.Method == http.MethodGet && dest != "object" && dest != "embed" {
+		return true
+	}
+
+	return false
+}
+
+func (fm *FetchMetadata) exempt(path string) bool {
+	return matchPath(fm.ExemptPaths, path)
+}
+
+// matchPath returns whether a request path matches any of the patterns.
+// A pattern ending in a slash matches every path below it, otherwise the path must match exactly.
+func matchPath(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") && strings.HasPrefix(path, pattern) {
+			return true
+		}
+
+		if path == pattern {
+			return true
+		}
+	}
+	return false
+}
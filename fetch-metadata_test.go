@@ -0,0 +1,111 @@
+package helmet
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchMetadata_Secure(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		site           string
+		mode           string
+		dest           string
+		expectedStatus int
+	}{
+		{name: "No Fetch Metadata", method: http.MethodPost, path: "/", expectedStatus: http.StatusOK},
+		{name: "Same Origin", method: http.MethodPost, path: "/", site: "same-origin", mode: "cors", dest: "empty", expectedStatus: http.StatusOK},
+		{name: "Same Site", method: http.MethodPost, path: "/", site: "same-site", mode: "cors", dest: "empty", expectedStatus: http.StatusOK},
+		{name: "User Initiated", method: http.MethodGet, path: "/", site: "none", mode: "navigate", dest: "document", expectedStatus: http.StatusOK},
+		{name: "Cross Site Navigation", method: http.MethodGet, path: "/", site: "cross-site", mode: "navigate", dest: "document", expectedStatus: http.StatusOK},
+		{name: "Cross Site Iframe Navigation", method: http.MethodGet, path: "/", site: "cross-site", mode: "navigate", dest: "iframe", expectedStatus: http.StatusOK},
+		{name: "Cross Site POST Navigation", method: http.MethodPost, path: "/", site: "cross-site", mode: "navigate", dest: "document", expectedStatus: http.StatusForbidden},
+		{name: "Cross Site Object", method: http.MethodGet, path: "/", site: "cross-site", mode: "navigate", dest: "object", expectedStatus: http.StatusForbidden},
+		{name: "Cross Site Embed", method: http.MethodGet, path: "/", site: "cross-site", mode: "navigate", dest: "embed", expectedStatus: http.StatusForbidden},
+		{name: "Cross Site Fetch", method: http.MethodGet, path: "/", site: "cross-site", mode: "cors", dest: "empty", expectedStatus: http.StatusForbidden},
+		{name: "Cross Site Script", method: http.MethodGet, path: "/", site: "cross-site", mode: "no-cors", dest: "script", expectedStatus: http.StatusForbidden},
+		{name: "Exempt Path", method: http.MethodGet, path: "/health", site: "cross-site", mode: "cors", dest: "empty", expectedStatus: http.StatusOK},
+		{name: "Exempt Prefix", method: http.MethodPost, path: "/api/public/v1", site: "cross-site", mode: "cors", dest: "empty", expectedStatus: http.StatusOK},
+		{name: "Exact Path Is Not A Prefix", method: http.MethodGet, path: "/health/deep", site: "cross-site", mode: "cors", dest: "empty", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.site != "" {
+				r.Header.Set(HeaderSecFetchSite, tc.site)
+				r.Header.Set(HeaderSecFetchMode, tc.mode)
+				r.Header.Set(HeaderSecFetchDest, tc.dest)
+			}
+
+			rr := httptest.NewRecorder()
+			NewFetchMetadata("/health", "/api/public/").Secure(mockNext).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected: %d\tActual: %d\n", tc.expectedStatus, resp.StatusCode)
+			}
+
+			if tc.expectedStatus == http.StatusOK {
+				testMockNext(t, resp)
+			}
+		})
+	}
+}
+
+func TestFetchMetadata_reportOnly(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	fm := NewFetchMetadata()
+	fm.ReportOnly = true
+	fm.Logger = log.New(&logs, "", 0)
+
+	r := httptest.NewRequest(http.MethodPost, "/transfer", nil)
+	r.Header.Set(HeaderSecFetchSite, "cross-site")
+	r.Header.Set(HeaderSecFetchMode, "no-cors")
+	r.Header.Set(HeaderSecFetchDest, "empty")
+
+	rr := httptest.NewRecorder()
+	fm.Secure(mockNext).ServeHTTP(rr, r)
+
+	testMockNext(t, rr.Result())
+
+	if !strings.Contains(logs.String(), "POST /transfer") {
+		t.Errorf("Rejected request should be logged\tActual: %s\n", logs.String())
+	}
+}
+
+func TestHelmet_Secure_fetchMetadata(t *testing.T) {
+	t.Parallel()
+
+	rr, r := newRecorderRequest(t)
+	r.Header.Set(HeaderSecFetchSite, "cross-site")
+	r.Header.Set(HeaderSecFetchMode, "cors")
+	r.Header.Set(HeaderSecFetchDest, "empty")
+
+	helmet := Default()
+	helmet.Use(NewFetchMetadata())
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+	resp := rr.Result()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected: %d\tActual: %d\n", http.StatusForbidden, resp.StatusCode)
+	}
+
+	// rejected responses still get the security headers
+	if header := resp.Header.Get(HeaderXContentTypeOptions); header != XContentTypeOptionsNoSniff.String() {
+		t.Errorf("Expected: %s\tActual: %s\n", XContentTypeOptionsNoSniff, header)
+	}
+}
@@ -0,0 +1,112 @@
+package helmet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ModuleFramingPolicy is the name used for Warnings about framing.
+const ModuleFramingPolicy = "Framing-Policy"
+
+// FramingPolicy represents who may embed responses in a frame. It is expressed by both the
+// X-Frame-Options HTTP security header, for older browsers, and the Content-Security-Policy
+// frame-ancestors directive, which takes precedence in newer ones.
+type FramingPolicy struct {
+	ancestors []CSPSource
+}
+
+// DenyFraming creates a new FramingPolicy that doesn't let anyone embed responses.
+func DenyFraming() FramingPolicy {
+	return FramingPolicy{[]CSPSource{SourceNone}}
+}
+
+// SameOriginFraming creates a new FramingPolicy that only lets the same origin embed responses.
+func SameOriginFraming() FramingPolicy {
+	return FramingPolicy{[]CSPSource{SourceSelf}}
+}
+
+// AllowFraming creates a new FramingPolicy that only lets the given origins embed responses, e.g. "https://example.com".
+// SourceSelf may be used for the same origin. Without origins it is equivalent to DenyFraming.
+func AllowFraming(origins ...CSPSource) FramingPolicy {
+	if len(origins) == 0 {
+		return DenyFraming()
+	}
+	return FramingPolicy{append([]CSPSource{}, origins...)}
+}
+
+// XFrameOptions returns the X-Frame-Options that expresses the FramingPolicy.
+// X-Frame-Options can't express an allowlist of other origins, so it is empty for those:
+// sending DENY or SAMEORIGIN would block the allowed origins in browsers that prefer it.
+func (fp FramingPolicy) XFrameOptions() XFrameOptions {
+	if len(fp.ancestors) != 1 {
+		return ""
+	}
+
+	switch fp.ancestors[0] {
+	case SourceNone:
+		return XFrameOptionsDeny
+	case SourceSelf:
+		return XFrameOptionsSameOrigin
+	default:
+		return ""
+	}
+}
+
+// FrameAncestors returns the Content-Security-Policy frame-ancestors sources that express the FramingPolicy.
+func (fp FramingPolicy) FrameAncestors() []CSPSource {
+	return append([]CSPSource{}, fp.ancestors...)
+}
+
+func (fp FramingPolicy) String() string {
+	sources := []string{}
+	for _, source := range fp.ancestors {
+		sources = append(sources, string(source))
+	}
+	return strings.Join(sources, " ")
+}
+
+// SetFramingPolicy sets both the X-Frame-Options and the Content-Security-Policy frame-ancestors directive
+// from the given FramingPolicy, replacing whatever they were configured to before.
+func (h *Helmet) SetFramingPolicy(fp FramingPolicy) {
+	h.XFrameOptions = fp.XFrameOptions()
+
+	if h.ContentSecurityPolicy == nil {
+		h.ContentSecurityPolicy = EmptyContentSecurityPolicy()
+	}
+	h.ContentSecurityPolicy.Remove(DirectiveFrameAncestors)
+	h.ContentSecurityPolicy.Add(DirectiveFrameAncestors, fp.FrameAncestors()...)
+}
+
+// framingWarnings flags an X-Frame-Options that disagrees with the Content-Security-Policy frame-ancestors directive.
+func (h *Helmet) framingWarnings() []Warning {
+	if h.XFrameOptions.Empty() || h.disabled[strings.ToLower(HeaderXFrameOptions)] {
+		return nil
+	}
+
+	if h.ContentSecurityPolicy == nil || h.disabled[strings.ToLower(HeaderContentSecurityPolicy)] {
+		return nil
+	}
+
+	ancestors, ok := h.ContentSecurityPolicy.policies[DirectiveFrameAncestors]
+	if !ok {
+		return nil
+	}
+
+	fp := AllowFraming(ancestors...)
+	expected := fp.XFrameOptions()
+	if h.XFrameOptions == expected {
+		return nil
+	}
+
+	if expected.Empty() {
+		return []Warning{{
+			ModuleFramingPolicy,
+			fmt.Sprintf("%s %s blocks frame-ancestors %s in browsers that don't support frame-ancestors, omit it instead", HeaderXFrameOptions, h.XFrameOptions, fp),
+		}}
+	}
+
+	return []Warning{{
+		ModuleFramingPolicy,
+		fmt.Sprintf("%s %s conflicts with frame-ancestors %s, which expects %s", HeaderXFrameOptions, h.XFrameOptions, fp, expected),
+	}}
+}
@@ -0,0 +1,121 @@
+package helmet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFramingPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                   string
+		framingPolicy          FramingPolicy
+		expectedXFrameOptions  XFrameOptions
+		expectedFrameAncestors string
+	}{
+		{name: "Deny", framingPolicy: DenyFraming(), expectedXFrameOptions: XFrameOptionsDeny, expectedFrameAncestors: "'none'"},
+		{name: "Same Origin", framingPolicy: SameOriginFraming(), expectedXFrameOptions: XFrameOptionsSameOrigin, expectedFrameAncestors: "'self'"},
+		{name: "Allow Nothing", framingPolicy: AllowFraming(), expectedXFrameOptions: XFrameOptionsDeny, expectedFrameAncestors: "'none'"},
+		{name: "Allow Self", framingPolicy: AllowFraming(SourceSelf), expectedXFrameOptions: XFrameOptionsSameOrigin, expectedFrameAncestors: "'self'"},
+		{
+			name:                   "Allow Origins",
+			framingPolicy:          AllowFraming(SourceSelf, "https://example.com"),
+			expectedXFrameOptions:  "",
+			expectedFrameAncestors: "'self' https://example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			xfo := tc.framingPolicy.XFrameOptions()
+			if xfo != tc.expectedXFrameOptions {
+				t.Errorf("Incorrect X-Frame-Options\tExpected: %s\tActual: %s\n", tc.expectedXFrameOptions, xfo)
+			}
+
+			helmet := Empty()
+			helmet.ContentSecurityPolicy.Add(DirectiveFrameAncestors, "https://stale.example.com")
+			helmet.SetFramingPolicy(tc.framingPolicy)
+
+			if helmet.XFrameOptions != tc.expectedXFrameOptions {
+				t.Errorf("Incorrect Helmet X-Frame-Options\tExpected: %s\tActual: %s\n", tc.expectedXFrameOptions, helmet.XFrameOptions)
+			}
+
+			csp := helmet.ContentSecurityPolicy.String()
+			if csp != "frame-ancestors "+tc.expectedFrameAncestors {
+				t.Errorf("Incorrect Content-Security-Policy\tExpected: frame-ancestors %s\tActual: %s\n", tc.expectedFrameAncestors, csp)
+			}
+
+			if warnings := helmet.framingWarnings(); len(warnings) != 0 {
+				t.Errorf("SetFramingPolicy should not conflict with itself\tActual: %v\n", warnings)
+			}
+		})
+	}
+}
+
+func TestHelmet_framingWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		configure        func(h *Helmet)
+		expectedWarnings []string // substrings, one per Warning, in order
+	}{
+		{name: "Default", configure: func(h *Helmet) {}, expectedWarnings: []string{}},
+		{
+			name: "Matching",
+			configure: func(h *Helmet) {
+				h.XFrameOptions = XFrameOptionsDeny
+				h.ContentSecurityPolicy.Add(DirectiveFrameAncestors, SourceNone)
+			},
+			expectedWarnings: []string{},
+		},
+		{
+			name: "Same Origin, Frame Ancestors None",
+			configure: func(h *Helmet) {
+				h.ContentSecurityPolicy.Add(DirectiveFrameAncestors, SourceNone)
+			},
+			expectedWarnings: []string{"X-Frame-Options SAMEORIGIN conflicts with frame-ancestors 'none', which expects DENY"},
+		},
+		{
+			name: "Deny, Frame Ancestors Allowlist",
+			configure: func(h *Helmet) {
+				h.XFrameOptions = XFrameOptionsDeny
+				h.ContentSecurityPolicy.Add(DirectiveFrameAncestors, SourceSelf, "https://example.com")
+			},
+			expectedWarnings: []string{"X-Frame-Options DENY blocks frame-ancestors 'self' https://example.com"},
+		},
+		{
+			name: "Disabled X-Frame-Options",
+			configure: func(h *Helmet) {
+				h.ContentSecurityPolicy.Add(DirectiveFrameAncestors, SourceNone)
+				h.Disable(HeaderXFrameOptions)
+			},
+			expectedWarnings: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			helmet := Default()
+			tc.configure(helmet)
+
+			warnings := helmet.framingWarnings()
+			if len(warnings) != len(tc.expectedWarnings) {
+				t.Fatalf("Incorrect warnings\tExpected: %v\tActual: %v\n", tc.expectedWarnings, warnings)
+			}
+
+			for i, warning := range warnings {
+				if !strings.Contains(warning.String(), tc.expectedWarnings[i]) {
+					t.Errorf("Incorrect warning\tExpected: %s\tActual: %s\n", tc.expectedWarnings[i], warning)
+				}
+			}
+		})
+	}
+}
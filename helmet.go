@@ -1,71 +1,216 @@
 package helmet
 
 import (
+	"io"
+	"log"
 	"net/http"
+	"strings"
 )
 
 // Helmet is a HTTP security middleware for Go(lang) inspired by HelmetJS for Express.js.
 type Helmet struct {
-	ContentSecurityPolicy         *ContentSecurityPolicy
-	XContentTypeOptions           XContentTypeOptions
-	XDNSPrefetchControl           XDNSPrefetchControl
-	XDownloadOptions              XDownloadOptions
-	ExpectCT                      *ExpectCT
-	FeaturePolicy                 *FeaturePolicy
-	XFrameOptions                 XFrameOptions
-	XPermittedCrossDomainPolicies XPermittedCrossDomainPolicies
-	XPoweredBy                    *XPoweredBy
-	ReferrerPolicy                *ReferrerPolicy
-	StrictTransportSecurity       *StrictTransportSecurity
-	XXSSProtection                *XXSSProtection
+	ContentSecurityPolicy           *ContentSecurityPolicy
+	ContentSecurityPolicyReportOnly *ContentSecurityPolicy
+	CrossOriginEmbedderPolicy       CrossOriginEmbedderPolicy
+	CrossOriginOpenerPolicy         CrossOriginOpenerPolicy
+	CrossOriginResourcePolicy       CrossOriginResourcePolicy
+	OriginAgentCluster              OriginAgentCluster
+	XContentTypeOptions             XContentTypeOptions
+	XDNSPrefetchControl             XDNSPrefetchControl
+	XDownloadOptions                XDownloadOptions
+	ExpectCT                        *ExpectCT
+	FeaturePolicy                   *FeaturePolicy
+	XFrameOptions                   XFrameOptions
+	XPermittedCrossDomainPolicies   XPermittedCrossDomainPolicies
+	XPoweredBy                      *XPoweredBy
+	ReferrerPolicy                  *ReferrerPolicy
+	StrictTransportSecurity         *StrictTransportSecurity
+	XXSSProtection                  *XXSSProtection
+	CORS                            *CORS
+
+	// Modules are custom Modules that are applied, in order, after the built-in ones.
+	Modules []Module
+
+	disabled map[string]bool
+
+	deprecationLogger  *log.Logger
+	stripDeprecated    bool
+	loggedDeprecations map[string]bool
+}
+
+// Option configures a Helmet at construction time.
+type Option func(*Helmet)
+
+// WithLegacyXXSSProtection restores the X-XSS-Protection default of earlier releases, "1; mode=block",
+// instead of "0". It exists only for compatibility with clients that still expect the auditor to be enabled.
+func WithLegacyXXSSProtection() Option {
+	return func(h *Helmet) {
+		h.XXSSProtection = BlockXXSSProtection()
+	}
 }
 
 // Default creates a new Helmet with default settings.
-func Default() *Helmet {
-	return &Helmet{
-		ContentSecurityPolicy:         EmptyContentSecurityPolicy(),
-		XContentTypeOptions:           XContentTypeOptionsNoSniff,
-		XDNSPrefetchControl:           XDNSPrefetchControlOff,
-		XDownloadOptions:              XDownloadOptionsNoOpen,
-		ExpectCT:                      EmptyExpectCT(),
-		FeaturePolicy:                 EmptyFeaturePolicy(),
-		XFrameOptions:                 XFrameOptionsSameOrigin,
-		XPermittedCrossDomainPolicies: "",
-		XPoweredBy:                    NewXPoweredBy(true, ""),
-		ReferrerPolicy:                EmptyReferrerPolicy(),
-		StrictTransportSecurity:       NewStrictTransportSecurity(5184000, true, false),
-		XXSSProtection:                NewXXSSProtection(true, DirectiveModeBlock, ""),
+func Default(options ...Option) *Helmet {
+	h := &Helmet{
+		ContentSecurityPolicy:           EmptyContentSecurityPolicy(),
+		ContentSecurityPolicyReportOnly: EmptyContentSecurityPolicy(),
+		XContentTypeOptions:             XContentTypeOptionsNoSniff,
+		XDNSPrefetchControl:             XDNSPrefetchControlOff,
+		XDownloadOptions:                XDownloadOptionsNoOpen,
+		ExpectCT:                        EmptyExpectCT(),
+		FeaturePolicy:                   EmptyFeaturePolicy(),
+		XFrameOptions:                   XFrameOptionsSameOrigin,
+		XPermittedCrossDomainPolicies:   "",
+		XPoweredBy:                      NewXPoweredBy(true, ""),
+		ReferrerPolicy:                  EmptyReferrerPolicy(),
+		StrictTransportSecurity:         NewStrictTransportSecurity(5184000, true, false),
+		XXSSProtection:                  DisabledXXSSProtection(),
+		CORS:                            EmptyCORS(),
+	}
+
+	for _, option := range options {
+		option(h)
 	}
+	h.handleDeprecated()
+	return h
 }
 
 // Empty creates a new Helmet.
-func Empty() *Helmet {
-	return &Helmet{
-		ContentSecurityPolicy:   EmptyContentSecurityPolicy(),
-		ExpectCT:                EmptyExpectCT(),
-		FeaturePolicy:           EmptyFeaturePolicy(),
-		XPoweredBy:              EmptyXPoweredBy(),
-		ReferrerPolicy:          EmptyReferrerPolicy(),
-		StrictTransportSecurity: EmptyStrictTransportSecurity(),
-		XXSSProtection:          EmptyXXSSProtection(),
+func Empty(options ...Option) *Helmet {
+	h := &Helmet{
+		ContentSecurityPolicy:           EmptyContentSecurityPolicy(),
+		ContentSecurityPolicyReportOnly: EmptyContentSecurityPolicy(),
+		ExpectCT:                        EmptyExpectCT(),
+		FeaturePolicy:                   EmptyFeaturePolicy(),
+		XPoweredBy:                      EmptyXPoweredBy(),
+		ReferrerPolicy:                  EmptyReferrerPolicy(),
+		StrictTransportSecurity:         EmptyStrictTransportSecurity(),
+		XXSSProtection:                  EmptyXXSSProtection(),
+		CORS:                            EmptyCORS(),
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+	h.handleDeprecated()
+	return h
+}
+
+// Use appends custom Modules, which are applied in the given order after the built-in ones.
+func (h *Helmet) Use(modules ...Module) {
+	h.Modules = append(h.Modules, modules...)
+}
+
+// Disable disables the Modules with the given names, built-in or custom.
+func (h *Helmet) Disable(names ...string) {
+	if h.disabled == nil {
+		h.disabled = make(map[string]bool)
+	}
+
+	for _, name := range names {
+		h.disabled[strings.ToLower(name)] = true
 	}
 }
 
+// Enable re-enables the Modules with the given names, built-in or custom.
+func (h *Helmet) Enable(names ...string) {
+	for _, name := range names {
+		delete(h.disabled, strings.ToLower(name))
+	}
+}
+
+// All returns every enabled Module in the order they are applied: the built-in ones followed by the custom ones.
+func (h *Helmet) All() []Module {
+	builtins := []Module{builtinModule{HeaderContentSecurityPolicy, h.ContentSecurityPolicy}}
+
+	// fields added after the first release are nil in Helmets built from a struct literal written before them
+	if h.ContentSecurityPolicyReportOnly != nil {
+		builtins = append(builtins, builtinModule{HeaderContentSecurityPolicyReportOnly, cspReportOnly{h.ContentSecurityPolicyReportOnly}})
+	}
+
+	builtins = append(builtins,
+		builtinModule{HeaderCrossOriginEmbedderPolicy, h.CrossOriginEmbedderPolicy},
+		builtinModule{HeaderCrossOriginOpenerPolicy, h.CrossOriginOpenerPolicy},
+		builtinModule{HeaderCrossOriginResourcePolicy, h.CrossOriginResourcePolicy},
+		builtinModule{HeaderOriginAgentCluster, h.OriginAgentCluster},
+		builtinModule{HeaderXContentTypeOptions, h.XContentTypeOptions},
+		builtinModule{HeaderXDNSPrefetchControl, h.XDNSPrefetchControl},
+		builtinModule{HeaderXDownloadOptions, h.XDownloadOptions},
+		builtinModule{HeaderExpectCT, h.ExpectCT},
+		builtinModule{HeaderFeaturePolicy, h.FeaturePolicy},
+		builtinModule{HeaderXFrameOptions, h.XFrameOptions},
+		builtinModule{HeaderXPermittedCrossDomainPolicies, h.XPermittedCrossDomainPolicies},
+		builtinModule{HeaderXPoweredBy, h.XPoweredBy},
+		builtinModule{HeaderReferrerPolicy, h.ReferrerPolicy},
+		builtinModule{HeaderStrictTransportSecurity, h.StrictTransportSecurity},
+		builtinModule{HeaderXXSSProtection, h.XXSSProtection},
+	)
+
+	if h.CORS != nil {
+		builtins = append(builtins, h.CORS)
+	}
+
+	modules := []Module{}
+	for _, module := range append(builtins, h.Modules...) {
+		if !h.disabled[strings.ToLower(module.Name())] {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
 // Secure is the middleware handler.
 func (h *Helmet) Secure(next http.Handler) http.Handler {
+	h.handleDeprecated()
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		h.ContentSecurityPolicy.Header(w)
-		h.XContentTypeOptions.Header(w)
-		h.XDNSPrefetchControl.Header(w)
-		h.XDownloadOptions.Header(w)
-		h.ExpectCT.Header(w)
-		h.FeaturePolicy.Header(w)
-		h.XFrameOptions.Header(w)
-		h.XPermittedCrossDomainPolicies.Header(w)
-		h.XPoweredBy.Header(w)
-		h.ReferrerPolicy.Header(w)
-		h.StrictTransportSecurity.Header(w)
-		h.XXSSProtection.Header(w)
+		if h.usesCSPNonce() {
+			r = withCSPNonce(r)
+		}
+
+		closers := []io.Closer{}
+		defer func() {
+			// close the innermost writer first, so that it can still write through the outer ones
+			for i := len(closers) - 1; i >= 0; i-- {
+				closers[i].Close()
+			}
+		}()
+
+		modules := []Module{}
+		for _, module := range h.All() {
+			if !module.Empty() {
+				modules = append(modules, module)
+			}
+		}
+
+		// Modules that answer requests themselves, like CORS preflights, come last, so that a request one of the
+		// other RequestFilters rejects is never answered, nor gets their headers.
+		for _, answers := range []bool{false, true} {
+			for _, module := range modules {
+				if _, ok := module.(requestAnswerer); ok != answers {
+					continue
+				}
+				module.Header(w, r)
+			}
+
+			for _, module := range modules {
+				if _, ok := module.(requestAnswerer); ok != answers {
+					continue
+				}
+				if filter, ok := module.(RequestFilter); ok && !filter.Allow(w, r) {
+					return
+				}
+			}
+		}
+
+		for _, module := range modules {
+			if wrapper, ok := module.(ResponseWrapper); ok {
+				w = wrapper.WrapResponseWriter(w, r)
+				if closer, ok := w.(io.Closer); ok {
+					closers = append(closers, closer)
+				}
+			}
+		}
 
 		next.ServeHTTP(w, r)
 	})
@@ -1,6 +1,8 @@
 package helmet
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -28,6 +30,11 @@ func TestHelmet_Secure_default(t *testing.T) {
 		header string
 	}{
 		{HeaderContentSecurityPolicy, ""},
+		{HeaderContentSecurityPolicyReportOnly, ""},
+		{HeaderCrossOriginEmbedderPolicy, ""},
+		{HeaderCrossOriginOpenerPolicy, ""},
+		{HeaderCrossOriginResourcePolicy, ""},
+		{HeaderOriginAgentCluster, ""},
 		{HeaderXContentTypeOptions, XContentTypeOptionsNoSniff.String()},
 		{HeaderXDNSPrefetchControl, XDNSPrefetchControlOff.String()},
 		{HeaderXDownloadOptions, XDownloadOptionsNoOpen.String()},
@@ -37,7 +44,7 @@ func TestHelmet_Secure_default(t *testing.T) {
 		{HeaderXPermittedCrossDomainPolicies, ""},
 		{HeaderReferrerPolicy, ""},
 		{HeaderStrictTransportSecurity, "max-age=5184000; includeSubDomains"},
-		{HeaderXXSSProtection, "1; mode=block"},
+		{HeaderXXSSProtection, "0"},
 	}
 
 	for _, tc := range testCases {
@@ -55,6 +62,20 @@ func TestHelmet_Secure_default(t *testing.T) {
 	testMockNext(t, resp)
 }
 
+func TestHelmet_Default_legacyXXSSProtection(t *testing.T) {
+	t.Parallel()
+
+	rr, r := newRecorderRequest(t)
+
+	helmet := Default(WithLegacyXXSSProtection())
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+
+	header := rr.Result().Header.Get(HeaderXXSSProtection)
+	if header != "1; mode=block" {
+		t.Errorf("Expected: %s\tActual: %s\n", "1; mode=block", header)
+	}
+}
+
 func TestHelmet_Secure_empty(t *testing.T) {
 	t.Parallel()
 
@@ -87,6 +108,11 @@ func TestHelmet_Secure_empty(t *testing.T) {
 		header string
 	}{
 		{HeaderContentSecurityPolicy},
+		{HeaderContentSecurityPolicyReportOnly},
+		{HeaderCrossOriginEmbedderPolicy},
+		{HeaderCrossOriginOpenerPolicy},
+		{HeaderCrossOriginResourcePolicy},
+		{HeaderOriginAgentCluster},
 		{HeaderXContentTypeOptions},
 		{HeaderXDNSPrefetchControl},
 		{HeaderXDownloadOptions},
@@ -123,6 +149,10 @@ func TestHelmet_Secure_custom(t *testing.T) {
 	helmet.ContentSecurityPolicy = NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
 		DirectiveDefaultSrc: {SourceNone},
 	})
+	helmet.CrossOriginEmbedderPolicy = CrossOriginEmbedderPolicyRequireCorp
+	helmet.CrossOriginOpenerPolicy = CrossOriginOpenerPolicySameOrigin
+	helmet.CrossOriginResourcePolicy = CrossOriginResourcePolicySameSite
+	helmet.OriginAgentCluster = OriginAgentClusterEnabled
 	helmet.XContentTypeOptions = XContentTypeOptionsNoSniff
 	helmet.XDNSPrefetchControl = XDNSPrefetchControlOn
 	helmet.XDownloadOptions = XDownloadOptionsNoOpen
@@ -145,6 +175,10 @@ func TestHelmet_Secure_custom(t *testing.T) {
 		header string
 	}{
 		{HeaderContentSecurityPolicy, "default-src 'none'"},
+		{HeaderCrossOriginEmbedderPolicy, CrossOriginEmbedderPolicyRequireCorp.String()},
+		{HeaderCrossOriginOpenerPolicy, CrossOriginOpenerPolicySameOrigin.String()},
+		{HeaderCrossOriginResourcePolicy, CrossOriginResourcePolicySameSite.String()},
+		{HeaderOriginAgentCluster, OriginAgentClusterEnabled.String()},
 		{HeaderXContentTypeOptions, XContentTypeOptionsNoSniff.String()},
 		{HeaderXDNSPrefetchControl, XDNSPrefetchControlOn.String()},
 		{HeaderXDownloadOptions, XDownloadOptionsNoOpen.String()},
@@ -172,3 +206,182 @@ func TestHelmet_Secure_custom(t *testing.T) {
 
 	testMockNext(t, resp)
 }
+
+func TestHelmet_Secure_structLiteral(t *testing.T) {
+	t.Parallel()
+
+	rr, r := newRecorderRequest(t)
+
+	// a Helmet built the way it had to be before Default and Empty set the newer fields
+	helmet := &Helmet{
+		ContentSecurityPolicy:   EmptyContentSecurityPolicy(),
+		XContentTypeOptions:     XContentTypeOptionsNoSniff,
+		ExpectCT:                EmptyExpectCT(),
+		FeaturePolicy:           EmptyFeaturePolicy(),
+		XPoweredBy:              EmptyXPoweredBy(),
+		ReferrerPolicy:          EmptyReferrerPolicy(),
+		StrictTransportSecurity: EmptyStrictTransportSecurity(),
+		XXSSProtection:          EmptyXXSSProtection(),
+	}
+
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+	resp := rr.Result()
+
+	if header := resp.Header.Get(HeaderXContentTypeOptions); header != XContentTypeOptionsNoSniff.String() {
+		t.Errorf("Expected: %s\tActual: %s\n", XContentTypeOptionsNoSniff, header)
+	}
+
+	if len(helmet.Warnings()) != 0 {
+		t.Errorf("Expected no warnings\tActual: %v\n", helmet.Warnings())
+	}
+
+	testMockNext(t, resp)
+}
+
+func TestHelmet_Use(t *testing.T) {
+	t.Parallel()
+
+	rr, r := newRecorderRequest(t)
+
+	helmet := Empty()
+	helmet.Use(mockModule{"X-Custom", "first"}, mockModule{"X-Other", "other"})
+	helmet.Use(mockModule{"X-Custom", "second"}, mockModule{"X-Empty", ""})
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+	resp := rr.Result()
+
+	testCases := []struct {
+		name   string
+		header string
+	}{
+		{"X-Custom", "second"},
+		{"X-Other", "other"},
+		{"X-Empty", ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := resp.Header.Get(tc.name)
+			if header != tc.header {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.header, header)
+			}
+		})
+	}
+
+	testMockNext(t, resp)
+}
+
+func TestHelmet_Disable(t *testing.T) {
+	t.Parallel()
+
+	rr, r := newRecorderRequest(t)
+
+	helmet := Default()
+	helmet.Use(mockModule{"X-Custom", "custom"}, mockModule{"X-Other", "other"})
+	helmet.Disable(HeaderXFrameOptions, "x-custom", HeaderStrictTransportSecurity)
+	helmet.Enable(HeaderStrictTransportSecurity)
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+	resp := rr.Result()
+
+	testCases := []struct {
+		name   string
+		header string
+	}{
+		{HeaderXFrameOptions, ""},
+		{"X-Custom", ""},
+		{"X-Other", "other"},
+		{HeaderStrictTransportSecurity, "max-age=5184000; includeSubDomains"},
+		{HeaderXContentTypeOptions, XContentTypeOptionsNoSniff.String()},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := resp.Header.Get(tc.name)
+			if header != tc.header {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.header, header)
+			}
+		})
+	}
+
+	testMockNext(t, resp)
+}
+
+func TestHelmet_All(t *testing.T) {
+	t.Parallel()
+
+	helmet := Empty()
+	helmet.Use(mockModule{"X-Custom", "custom"})
+	helmet.Disable(HeaderXPoweredBy)
+
+	modules := helmet.All()
+	if len(modules) != 18 {
+		t.Fatalf("Incorrect amount of Modules\tExpected: %d\tActual: %d\n", 18, len(modules))
+	}
+
+	if name := modules[0].Name(); name != HeaderContentSecurityPolicy {
+		t.Errorf("Built-in Modules should come first\tExpected: %s\tActual: %s\n", HeaderContentSecurityPolicy, name)
+	}
+
+	if name := modules[len(modules)-1].Name(); name != "X-Custom" {
+		t.Errorf("Custom Modules should come last\tExpected: %s\tActual: %s\n", "X-Custom", name)
+	}
+
+	for _, module := range modules {
+		if module.Name() == HeaderXPoweredBy {
+			t.Errorf("Disabled Module should be excluded\tModule: %s\n", module.Name())
+		}
+	}
+}
+
+func TestHelmet_Secure_requestAware(t *testing.T) {
+	t.Parallel()
+
+	helmet := Empty()
+	helmet.Use(NewClearSiteData([]string{"/logout"}, DirectiveCookies))
+
+	testCases := []struct {
+		name   string
+		path   string
+		header string
+	}{
+		{name: "Logout", path: "/logout", header: `"cookies"`},
+		{name: "Index", path: "/", header: ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			helmet.Secure(mockNext).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			resp := rr.Result()
+
+			header := resp.Header.Get(HeaderClearSiteData)
+			if header != tc.header {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.header, header)
+			}
+
+			testMockNext(t, resp)
+		})
+	}
+}
+
+func TestHelmet_Secure_plainHTTP(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	Default().Secure(mockNext).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+	resp := rr.Result()
+
+	if header := resp.Header.Get(HeaderStrictTransportSecurity); header != "" {
+		t.Errorf("Strict-Transport-Security should not be sent over plain HTTP\tActual: %s\n", header)
+	}
+
+	testMockNext(t, resp)
+}
@@ -0,0 +1,70 @@
+package helmet
+
+// HelmetJSVersion represents a major version of HelmetJS whose defaults Helmet can reproduce.
+type HelmetJSVersion int
+
+// List of all supported HelmetJS major versions.
+const (
+	HelmetJSv6 HelmetJSVersion = 6
+	HelmetJSv7 HelmetJSVersion = 7
+	HelmetJSv8 HelmetJSVersion = 8
+
+	// HelmetJSLatest is the newest HelmetJS major version that Helmet can reproduce.
+	HelmetJSLatest = HelmetJSv8
+)
+
+// HelmetJS creates a new Helmet that sends the same headers as the given HelmetJS major version does by default.
+// The Content-Security-Policy directives may come in another order, which browsers treat the same.
+// Unsupported versions fall back to HelmetJSLatest.
+func HelmetJS(version HelmetJSVersion, options ...Option) *Helmet {
+	if version < HelmetJSv6 || version > HelmetJSLatest {
+		version = HelmetJSLatest
+	}
+
+	h := Empty()
+	h.ContentSecurityPolicy = helmetJSContentSecurityPolicy()
+	h.CrossOriginOpenerPolicy = CrossOriginOpenerPolicySameOrigin
+	h.CrossOriginResourcePolicy = CrossOriginResourcePolicySameOrigin
+	h.OriginAgentCluster = OriginAgentClusterEnabled
+	h.ReferrerPolicy = NewReferrerPolicy(DirectiveNoReferrer)
+	h.StrictTransportSecurity = NewStrictTransportSecurity(15552000, true, false)
+	h.XContentTypeOptions = XContentTypeOptionsNoSniff
+	h.XDNSPrefetchControl = XDNSPrefetchControlOff
+	h.XDownloadOptions = XDownloadOptionsNoOpen
+	h.XFrameOptions = XFrameOptionsSameOrigin
+	h.XPermittedCrossDomainPolicies = PermittedCrossDomainPoliciesNone
+	h.XPoweredBy = NewXPoweredBy(true, "")
+	h.XXSSProtection = NewXXSSProtection(false, "", "")
+
+	// v7 stopped sending Cross-Origin-Embedder-Policy by default
+	if version == HelmetJSv6 {
+		h.CrossOriginEmbedderPolicy = CrossOriginEmbedderPolicyRequireCorp
+	}
+
+	// v8 raised the Strict-Transport-Security max-age from 180 days to 365 days
+	if version >= HelmetJSv8 {
+		h.StrictTransportSecurity = NewStrictTransportSecurity(31536000, true, false)
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+	h.handleDeprecated()
+	return h
+}
+
+func helmetJSContentSecurityPolicy() *ContentSecurityPolicy {
+	return NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
+		DirectiveDefaultSrc:              {SourceSelf},
+		DirectiveBaseURI:                 {SourceSelf},
+		DirectiveFontSrc:                 {SourceSelf, SourceHTTPS, SourceData},
+		DirectiveFormAction:              {SourceSelf},
+		DirectiveFrameAncestors:          {SourceSelf},
+		DirectiveImgSrc:                  {SourceSelf, SourceData},
+		DirectiveObjectSrc:               {SourceNone},
+		DirectiveScriptSrc:               {SourceSelf},
+		DirectiveScriptSrcAttr:           {SourceNone},
+		DirectiveStyleSrc:                {SourceSelf, SourceHTTPS, SourceUnsafeInline},
+		DirectiveUpgradeInsecureRequests: {},
+	})
+}
@@ -0,0 +1,124 @@
+package helmet
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var mockEmptyNext = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+// readGoldenHeaders reads a golden fixture containing one "Header: value" pair per line.
+func readGoldenHeaders(t *testing.T, path string) http.Header {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	headers := http.Header{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			t.Fatalf("Malformed golden line: %s\n", line)
+		}
+		headers.Set(name, value)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return headers
+}
+
+// normalizeCSP makes a Content-Security-Policy independent of directive order and separator whitespace.
+func normalizeCSP(policy string) string {
+	directives := []string{}
+	for _, directive := range strings.Split(policy, ";") {
+		directive = strings.TrimSpace(directive)
+		if directive != "" {
+			directives = append(directives, directive)
+		}
+	}
+	sort.Strings(directives)
+	return strings.Join(directives, "; ")
+}
+
+func TestHelmetJS(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		version HelmetJSVersion
+	}{
+		{HelmetJSv6},
+		{HelmetJSv7},
+		{HelmetJSv8},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("v%d", tc.version), func(t *testing.T) {
+			t.Parallel()
+
+			expected := readGoldenHeaders(t, fmt.Sprintf("testdata/helmetjs/v%d.golden", tc.version))
+
+			rr, r := newRecorderRequest(t)
+			addXPoweredByHelmetMiddleware(HelmetJS(tc.version).Secure(mockEmptyNext)).ServeHTTP(rr, r)
+			actual := rr.Result().Header
+
+			for name := range expected {
+				expectedHeader, header := expected.Get(name), actual.Get(name)
+				if name == HeaderContentSecurityPolicy {
+					expectedHeader, header = normalizeCSP(expectedHeader), normalizeCSP(header)
+				}
+
+				if header != expectedHeader {
+					t.Errorf("Incorrect %s\tExpected: %s\tActual: %s\n", name, expectedHeader, header)
+				}
+			}
+
+			for name := range actual {
+				if _, ok := expected[name]; !ok {
+					t.Errorf("Unexpected header\tHeader: %s\tValue: %s\n", name, actual.Get(name))
+				}
+			}
+		})
+	}
+}
+
+func TestHelmetJS_unsupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		version HelmetJSVersion
+	}{
+		{name: "Too Old", version: 4},
+		{name: "Too New", version: HelmetJSLatest + 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			helmet, latest := HelmetJS(tc.version), HelmetJS(HelmetJSLatest)
+			if helmet.StrictTransportSecurity.String() != latest.StrictTransportSecurity.String() {
+				t.Errorf("Expected: %s\tActual: %s\n", latest.StrictTransportSecurity, helmet.StrictTransportSecurity)
+			}
+
+			if helmet.CrossOriginEmbedderPolicy != latest.CrossOriginEmbedderPolicy {
+				t.Errorf("Expected: %s\tActual: %s\n", latest.CrossOriginEmbedderPolicy, helmet.CrossOriginEmbedderPolicy)
+			}
+		})
+	}
+}
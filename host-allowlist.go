@@ -0,0 +1,127 @@
+package helmet
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// HeaderXForwardedHost is the HTTP header that reverse proxies use to forward the original request's Host.
+const HeaderXForwardedHost = "X-Forwarded-Host"
+
+// ModuleHostAllowlist is the name of the HostAllowlist Module.
+const ModuleHostAllowlist = "Host-Allowlist"
+
+// HostAllowlist rejects requests for hosts that aren't explicitly allowed,
+// which protects against Host header injection and DNS rebinding.
+type HostAllowlist struct {
+	// Allowed hosts, matched case-insensitively:
+	// "example.com" matches on any port, "example.com:8443" only on that port,
+	// "*.example.com" matches every subdomain (but not example.com itself),
+	// and IPv6 literals are written in brackets, e.g. "[::1]" or "[::1]:8080".
+	Hosts []string
+
+	// Whether every host in the X-Forwarded-Host HTTP header must be allowed as well.
+	CheckForwardedHost bool
+
+	// Writes the response for rejected requests. Defaults to 400 Bad Request.
+	ErrorHandler http.Handler
+}
+
+// NewHostAllowlist creates a new HostAllowlist.
+func NewHostAllowlist(hosts ...string) *HostAllowlist {
+	return &HostAllowlist{Hosts: hosts}
+}
+
+// Name returns the name of the HostAllowlist Module.
+func (ha *HostAllowlist) Name() string {
+	return ModuleHostAllowlist
+}
+
+// Empty returns whether the HostAllowlist is empty.
+func (ha *HostAllowlist) Empty() bool {
+	return len(ha.Hosts) == 0
+}
+
+// Header does nothing, since HostAllowlist only inspects requests.
+func (ha *HostAllowlist) Header(w http.ResponseWriter, r *http.Request) {}
+
+// Allow returns whether the http.Request is for an allowed host, rejecting it if not.
+func (ha *HostAllowlist) Allow(w http.ResponseWriter, r *http.Request) bool {
+	if ha.allowed(r) {
+		return true
+	}
+
+	if ha.ErrorHandler != nil {
+		ha.ErrorHandler.ServeHTTP(w, r)
+	} else {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	}
+	return false
+}
+
+// Secure is the middleware handler, for using HostAllowlist without Helmet.
+func (ha *HostAllowlist) Secure(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ha.Allow(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// Allows returns whether the given Host HTTP header is allowed.
+func (ha *HostAllowlist) Allows(hostport string) bool {
+	host, port := splitHostPort(hostport)
+	for _, pattern := range ha.Hosts {
+		if matchHost(pattern, host, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ha *HostAllowlist) allowed(r *http.Request) bool {
+	if !ha.Allows(r.Host) {
+		return false
+	}
+
+	if ha.CheckForwardedHost {
+		for _, forwarded := range r.Header.Values(HeaderXForwardedHost) {
+			for _, hostport := range strings.Split(forwarded, ",") {
+				if !ha.Allows(strings.TrimSpace(hostport)) {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// matchHost returns whether a host and (possibly empty) port match a host pattern.
+func matchHost(pattern string, host string, port string) bool {
+	if host == "" {
+		return false
+	}
+
+	patternHost, patternPort := splitHostPort(pattern)
+	if patternPort != "" && patternPort != "*" && patternPort != port {
+		return false
+	}
+
+	// IP addresses are compared by value, so that different spellings of the same IPv6 address match
+	if patternAddr, err := netip.ParseAddr(patternHost); err == nil {
+		addr, err := netip.ParseAddr(host)
+		return err == nil && addr.Unmap() == patternAddr.Unmap()
+	}
+
+	patternHost = strings.TrimSuffix(strings.ToLower(patternHost), ".")
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if strings.HasPrefix(patternHost, "*.") {
+		suffix := patternHost[1:]
+		return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+	}
+
+	return host == patternHost
+}
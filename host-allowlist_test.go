@@ -0,0 +1,174 @@
+package helmet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostAllowlist_Allows(t *testing.T) {
+	t.Parallel()
+
+	ha := NewHostAllowlist("example.com", "*.example.org", "admin.internal:8443", "localhost:*", "[::1]", "[2001:db8::1]:8080", "127.0.0.1")
+
+	testCases := []struct {
+		host          string
+		expectedAllow bool
+	}{
+		{host: "example.com", expectedAllow: true},
+		{host: "example.com:8080", expectedAllow: true},
+		{host: "EXAMPLE.COM", expectedAllow: true},
+		{host: "Example.Com:443", expectedAllow: true},
+		{host: "example.com.", expectedAllow: true},
+		{host: "www.example.com", expectedAllow: false},
+		{host: "example.com.evil.com", expectedAllow: false},
+		{host: "api.example.org", expectedAllow: true},
+		{host: "API.Example.ORG:3000", expectedAllow: true},
+		{host: "a.b.example.org", expectedAllow: true},
+		{host: "example.org", expectedAllow: false},
+		{host: "evilexample.org", expectedAllow: false},
+		{host: "admin.internal:8443", expectedAllow: true},
+		{host: "admin.internal", expectedAllow: false},
+		{host: "admin.internal:80", expectedAllow: false},
+		{host: "localhost", expectedAllow: true},
+		{host: "localhost:3000", expectedAllow: true},
+		{host: "[::1]", expectedAllow: true},
+		{host: "[::1]:3000", expectedAllow: true},
+		{host: "[0:0:0:0:0:0:0:1]:3000", expectedAllow: true},
+		{host: "[::2]", expectedAllow: false},
+		{host: "[2001:db8::1]:8080", expectedAllow: true},
+		{host: "[2001:DB8:0::1]:8080", expectedAllow: true},
+		{host: "[2001:db8::1]:9090", expectedAllow: false},
+		{host: "127.0.0.1:8080", expectedAllow: true},
+		{host: "[::ffff:127.0.0.1]", expectedAllow: true},
+		{host: "attacker.com", expectedAllow: false},
+		{host: "", expectedAllow: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.host, func(t *testing.T) {
+			t.Parallel()
+
+			allow := ha.Allows(tc.host)
+			if allow != tc.expectedAllow {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedAllow, allow)
+			}
+		})
+	}
+}
+
+func TestHostAllowlist_Secure(t *testing.T) {
+	t.Parallel()
+
+	teapot := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	testCases := []struct {
+		name               string
+		host               string
+		forwardedHost      string
+		checkForwardedHost bool
+		errorHandler       http.Handler
+		expectedStatus     int
+	}{
+		{name: "Allowed", host: "example.com", expectedStatus: http.StatusOK},
+		{name: "Rejected", host: "attacker.com", expectedStatus: http.StatusBadRequest},
+		{name: "Custom Error Response", host: "attacker.com", errorHandler: teapot, expectedStatus: http.StatusTeapot},
+		{name: "Forwarded Host Ignored", host: "example.com", forwardedHost: "attacker.com", expectedStatus: http.StatusOK},
+		{name: "Forwarded Host Allowed", host: "example.com", forwardedHost: "example.com", checkForwardedHost: true, expectedStatus: http.StatusOK},
+		{name: "Forwarded Host Rejected", host: "example.com", forwardedHost: "attacker.com", checkForwardedHost: true, expectedStatus: http.StatusBadRequest},
+		{name: "Forwarded Host Chain Rejected", host: "example.com", forwardedHost: "example.com, attacker.com", checkForwardedHost: true, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ha := NewHostAllowlist("example.com")
+			ha.CheckForwardedHost = tc.checkForwardedHost
+			ha.ErrorHandler = tc.errorHandler
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = tc.host
+			if tc.forwardedHost != "" {
+				r.Header.Set(HeaderXForwardedHost, tc.forwardedHost)
+			}
+
+			rr := httptest.NewRecorder()
+			ha.Secure(mockNext).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected: %d\tActual: %d\n", tc.expectedStatus, resp.StatusCode)
+			}
+
+			if tc.expectedStatus == http.StatusOK {
+				testMockNext(t, resp)
+			}
+		})
+	}
+}
+
+func TestHelmet_Secure_hostAllowlist(t *testing.T) {
+	t.Parallel()
+
+	rr, r := newRecorderRequest(t)
+	r.Host = "rebound.attacker.com"
+
+	helmet := Default()
+	helmet.Use(NewHostAllowlist("dashboard.internal"))
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+
+	if status := rr.Result().StatusCode; status != http.StatusBadRequest {
+		t.Errorf("Expected: %d\tActual: %d\n", http.StatusBadRequest, status)
+	}
+}
+
+func TestHelmet_Secure_hostAllowlistPreflight(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                   string
+		host                   string
+		expectedStatus         int
+		expectedAllowOrigin    string
+		expectedSecurityHeader string
+	}{
+		{name: "Allowed Host", host: "api.example.com", expectedStatus: http.StatusNoContent, expectedAllowOrigin: "https://app.example.com", expectedSecurityHeader: XFrameOptionsSameOrigin.String()},
+		{name: "Rebound Host", host: "evil.attacker", expectedStatus: http.StatusBadRequest, expectedAllowOrigin: "", expectedSecurityHeader: XFrameOptionsSameOrigin.String()},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr, r := newRecorderRequest(t)
+			r.Method = http.MethodOptions
+			r.Host = tc.host
+			r.Header.Set(HeaderOrigin, "https://app.example.com")
+			r.Header.Set(HeaderAccessControlRequestMethod, http.MethodPut)
+
+			helmet := Default()
+			helmet.CORS = NewCORS("https://app.example.com")
+			helmet.Use(NewHostAllowlist("api.example.com"))
+			helmet.Secure(mockNext).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Incorrect status code\tExpected: %d\tActual: %d\n", tc.expectedStatus, resp.StatusCode)
+			}
+
+			if header := resp.Header.Get(HeaderAccessControlAllowOrigin); header != tc.expectedAllowOrigin {
+				t.Errorf("Incorrect Access-Control-Allow-Origin\tExpected: %s\tActual: %s\n", tc.expectedAllowOrigin, header)
+			}
+
+			if header := resp.Header.Get(HeaderXFrameOptions); header != tc.expectedSecurityHeader {
+				t.Errorf("Incorrect X-Frame-Options\tExpected: %s\tActual: %s\n", tc.expectedSecurityHeader, header)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package helmet
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HSTSPreloadMinMaxAge is the minimum Strict-Transport-Security max-age, one year, accepted by the HSTS preload list.
+const HSTSPreloadMinMaxAge = 31536000
+
+// List of all HSTS preload list requirements that can be checked offline.
+const (
+	PreloadRequirementMaxAge            PreloadRequirement = "max-age"
+	PreloadRequirementIncludeSubDomains PreloadRequirement = "includeSubDomains"
+	PreloadRequirementPreload           PreloadRequirement = "preload"
+	PreloadRequirementHTTPSRedirect     PreloadRequirement = "https-redirect"
+	PreloadRequirementHeaderOnHTTPS     PreloadRequirement = "header-on-https"
+)
+
+type (
+	// PreloadRequirement represents a requirement of the HSTS preload list (https://hstspreload.org).
+	PreloadRequirement string
+
+	// PreloadResult represents the outcome of checking a single PreloadRequirement.
+	PreloadResult struct {
+		Requirement PreloadRequirement
+		Passed      bool
+		Reason      string // why the requirement failed, empty when it passed
+	}
+
+	// PreloadReport represents the outcome of checking every PreloadRequirement.
+	PreloadReport struct {
+		Results []PreloadResult
+	}
+)
+
+// Eligible returns whether every PreloadRequirement passed.
+func (pr *PreloadReport) Eligible() bool {
+	return len(pr.Failures()) == 0
+}
+
+// Failures returns the PreloadResults that did not pass.
+func (pr *PreloadReport) Failures() []PreloadResult {
+	failures := []PreloadResult{}
+	for _, result := range pr.Results {
+		if !result.Passed {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+func (pr *PreloadReport) add(requirement PreloadRequirement, reason string) {
+	pr.Results = append(pr.Results, PreloadResult{requirement, reason == "", reason})
+}
+
+// CheckPreload checks, without touching the network, whether the Strict-Transport-Security and the given http.Handler
+// serving the apex host meet the HSTS preload list requirements.
+// Requirements that need the network, such as a valid certificate, are not checked.
+func (hsts *StrictTransportSecurity) CheckPreload(handler http.Handler, apexHost string) *PreloadReport {
+	report := &PreloadReport{}
+	maxAge, preload := hsts.effective()
+
+	reason := ""
+	if maxAge < HSTSPreloadMinMaxAge {
+		reason = fmt.Sprintf("max-age is %d, but must be at least %d", maxAge, HSTSPreloadMinMaxAge)
+	}
+	report.add(PreloadRequirementMaxAge, reason)
+
+	reason = ""
+	if !hsts.IncludeSubDomains {
+		reason = "includeSubDomains directive is missing"
+	}
+	report.add(PreloadRequirementIncludeSubDomains, reason)
+
+	reason = ""
+	if !preload {
+		reason = "preload directive is missing"
+	}
+	report.add(PreloadRequirementPreload, reason)
+
+	report.add(PreloadRequirementHTTPSRedirect, checkPreloadHTTPSRedirect(handler, apexHost))
+	report.add(PreloadRequirementHeaderOnHTTPS, checkPreloadHeaderOnHTTPS(handler, apexHost))
+
+	return report
+}
+
+// checkPreloadHTTPSRedirect checks that plain HTTP requests are redirected to HTTPS on the same host.
+func checkPreloadHTTPSRedirect(handler http.Handler, apexHost string) string {
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://"+apexHost+"/", nil))
+	resp := rr.Result()
+
+	if resp.StatusCode < 300 || resp.StatusCode > 399 {
+		return fmt.Sprintf("plain HTTP request responded with %d instead of a redirect", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || location.Scheme != "https" {
+		return fmt.Sprintf("plain HTTP request redirected to %q instead of HTTPS", resp.Header.Get("Location"))
+	}
+
+	if !strings.EqualFold(location.Hostname(), apexHost) {
+		return fmt.Sprintf("plain HTTP request redirected to %q instead of HTTPS on the same host first", location.Host)
+	}
+
+	return ""
+}
+
+// checkPreloadHeaderOnHTTPS checks that HTTPS responses, including redirects, carry a preloadable Strict-Transport-Security.
+func checkPreloadHeaderOnHTTPS(handler http.Handler, apexHost string) string {
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "https://"+apexHost+"/", nil))
+	resp := rr.Result()
+
+	header := resp.Header.Get(HeaderStrictTransportSecurity)
+	if header == "" {
+		return fmt.Sprintf("HTTPS response (status %d) has no Strict-Transport-Security header", resp.StatusCode)
+	}
+
+	maxAge, includeSubDomains, preload := -1, false, false
+	for _, directive := range strings.Split(header, ";") {
+		directive = strings.TrimSpace(directive)
+		if name, value, ok := strings.Cut(directive, "="); ok && strings.EqualFold(name, "max-age") {
+			if parsed, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				maxAge = parsed
+			}
+		} else if strings.EqualFold(directive, string(DirectiveIncludeSubDomains)) {
+			includeSubDomains = true
+		} else if strings.EqualFold(directive, string(DirectivePreload)) {
+			preload = true
+		}
+	}
+
+	if maxAge < HSTSPreloadMinMaxAge || !includeSubDomains || !preload {
+		return fmt.Sprintf("HTTPS response (status %d) has a Strict-Transport-Security header that is not preloadable: %q", resp.StatusCode, header)
+	}
+
+	return ""
+}
@@ -0,0 +1,118 @@
+package helmet
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStrictTransportSecurity_CheckPreload(t *testing.T) {
+	t.Parallel()
+
+	helmetHandler := func(hsts *StrictTransportSecurity, next http.Handler) http.Handler {
+		helmet := Empty()
+		helmet.StrictTransportSecurity = hsts
+		return helmet.Secure(next)
+	}
+
+	// secureHandler wraps next in Helmet (with the given HSTS) and an HTTPSRedirect, like a real deployment would
+	secureHandler := func(hsts *StrictTransportSecurity, next http.Handler) http.Handler {
+		return NewHTTPSRedirect(hsts, "example.com").Secure(helmetHandler(hsts, next))
+	}
+
+	wwwRedirect := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://www.example.com/", http.StatusMovedPermanently)
+	})
+
+	preloadable := NewStrictTransportSecurity(63072000, true, true)
+
+	testCases := []struct {
+		name             string
+		hsts             *StrictTransportSecurity
+		handler          http.Handler
+		expectedFailures []PreloadRequirement
+	}{
+		{
+			name:             "Eligible",
+			hsts:             preloadable,
+			handler:          secureHandler(preloadable, mockNext),
+			expectedFailures: []PreloadRequirement{},
+		},
+		{
+			name:             "Eligible, HTTPS Redirect With Header",
+			hsts:             preloadable,
+			handler:          secureHandler(preloadable, wwwRedirect),
+			expectedFailures: []PreloadRequirement{},
+		},
+		{
+			name:    "Short Max Age",
+			hsts:    NewStrictTransportSecurity(300, true, true),
+			handler: secureHandler(NewStrictTransportSecurity(300, true, true), mockNext),
+			expectedFailures: []PreloadRequirement{
+				PreloadRequirementMaxAge,
+				PreloadRequirementHeaderOnHTTPS,
+			},
+		},
+		{
+			name:    "Missing Directives",
+			hsts:    NewStrictTransportSecurity(63072000, false, false),
+			handler: secureHandler(NewStrictTransportSecurity(63072000, false, false), mockNext),
+			expectedFailures: []PreloadRequirement{
+				PreloadRequirementIncludeSubDomains,
+				PreloadRequirementPreload,
+				PreloadRequirementHeaderOnHTTPS,
+			},
+		},
+		{
+			name:             "No HTTPS Redirect",
+			hsts:             preloadable,
+			handler:          helmetHandler(preloadable, mockNext),
+			expectedFailures: []PreloadRequirement{PreloadRequirementHTTPSRedirect},
+		},
+		{
+			name:             "Redirect To Another Host",
+			hsts:             preloadable,
+			handler:          helmetHandler(preloadable, wwwRedirect),
+			expectedFailures: []PreloadRequirement{PreloadRequirementHTTPSRedirect},
+		},
+		{
+			name:    "HTTPS Redirect Without Header",
+			hsts:    preloadable,
+			handler: NewHTTPSRedirect(preloadable, "example.com").Secure(wwwRedirect),
+			expectedFailures: []PreloadRequirement{
+				PreloadRequirementHeaderOnHTTPS,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := tc.hsts.CheckPreload(tc.handler, "example.com")
+
+			if len(report.Results) != 5 {
+				t.Errorf("Every requirement should have a result\tExpected: %d\tActual: %d\n", 5, len(report.Results))
+			}
+
+			failures := report.Failures()
+			if len(failures) != len(tc.expectedFailures) {
+				t.Fatalf("Incorrect failures\tExpected: %v\tActual: %v\n", tc.expectedFailures, failures)
+			}
+
+			for i, failure := range failures {
+				if failure.Requirement != tc.expectedFailures[i] {
+					t.Errorf("Incorrect failure\tExpected: %s\tActual: %s\n", tc.expectedFailures[i], failure.Requirement)
+				}
+
+				if failure.Reason == "" {
+					t.Errorf("Failure should have a reason\tRequirement: %s\n", failure.Requirement)
+				}
+			}
+
+			if eligible := report.Eligible(); eligible != (len(tc.expectedFailures) == 0) {
+				t.Errorf("Incorrect Eligible\tExpected: %t\tActual: %t\n", len(tc.expectedFailures) == 0, eligible)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package helmet
+
+import "time"
+
+type (
+	// HSTSStage represents a single step of an HSTSSchedule.
+	HSTSStage struct {
+		// The Strict-Transport-Security max-age, in seconds, sent during this stage.
+		MaxAge int
+
+		// How long this stage lasts before the next one begins. The last stage lasts forever.
+		Duration time.Duration
+
+		// Whether the preload directive is sent during this stage.
+		Preload bool
+	}
+
+	// HSTSSchedule gradually raises the Strict-Transport-Security max-age, so that a misconfiguration can be noticed
+	// while browsers only remember it for a short time.
+	HSTSSchedule struct {
+		// When the first stage begins. Until then the first stage is used.
+		Start time.Time
+
+		Stages []HSTSStage
+
+		// Returns the current time. Defaults to time.Now.
+		Clock func() time.Time
+	}
+)
+
+// NewHSTSSchedule creates a new HSTSSchedule.
+func NewHSTSSchedule(start time.Time, stages ...HSTSStage) *HSTSSchedule {
+	return &HSTSSchedule{
+		Start:  start,
+		Stages: stages,
+		Clock:  time.Now,
+	}
+}
+
+// Current returns the HSTSStage that is in effect right now, or the zero HSTSStage if there are no stages.
+func (s *HSTSSchedule) Current() HSTSStage {
+	if len(s.Stages) == 0 {
+		return HSTSStage{}
+	}
+	return s.Stages[s.current()]
+}
+
+// current returns the index of the HSTSStage that is in effect right now, or 0 if there are no stages.
+func (s *HSTSSchedule) current() int {
+	if len(s.Stages) == 0 {
+		return 0
+	}
+
+	now := time.Now
+	if s.Clock != nil {
+		now = s.Clock
+	}
+
+	elapsed := now().Sub(s.Start)
+	for i, stage := range s.Stages[:len(s.Stages)-1] {
+		if elapsed < stage.Duration {
+			return i
+		}
+		elapsed -= stage.Duration
+	}
+	return len(s.Stages) - 1
+}
@@ -0,0 +1,167 @@
+package helmet
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for HSTSSchedule tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+var (
+	scheduleStart = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	rampUpStages = []HSTSStage{
+		{MaxAge: 300, Duration: 24 * time.Hour},
+		{MaxAge: 604800, Duration: 7 * 24 * time.Hour},
+		{MaxAge: 2592000, Duration: 30 * 24 * time.Hour},
+		{MaxAge: 63072000, Duration: 30 * 24 * time.Hour},
+		{MaxAge: 63072000, Preload: true},
+	}
+)
+
+func TestHSTSSchedule_New(t *testing.T) {
+	t.Parallel()
+
+	schedule := NewHSTSSchedule(scheduleStart, rampUpStages...)
+
+	if !schedule.Start.Equal(scheduleStart) {
+		t.Errorf("Incorrect Start\tExpected: %s\tActual: %s\n", scheduleStart, schedule.Start)
+	}
+
+	if len(schedule.Stages) != len(rampUpStages) {
+		t.Errorf("Incorrect amount of stages\tExpected: %d\tActual: %d\n", len(rampUpStages), len(schedule.Stages))
+	}
+
+	if schedule.Clock == nil {
+		t.Errorf("Clock should default to time.Now\n")
+	}
+}
+
+func TestHSTSSchedule_Current(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		elapsed        time.Duration
+		expectedMaxAge int
+		expectedStage  int
+	}{
+		{name: "Before Start", elapsed: -time.Hour, expectedMaxAge: 300, expectedStage: 0},
+		{name: "Start", elapsed: 0, expectedMaxAge: 300, expectedStage: 0},
+		{name: "End Of First Stage", elapsed: 24*time.Hour - time.Second, expectedMaxAge: 300, expectedStage: 0},
+		{name: "Second Stage", elapsed: 24 * time.Hour, expectedMaxAge: 604800, expectedStage: 1},
+		{name: "Third Stage", elapsed: 8 * 24 * time.Hour, expectedMaxAge: 2592000, expectedStage: 2},
+		{name: "Fourth Stage", elapsed: 38 * 24 * time.Hour, expectedMaxAge: 63072000, expectedStage: 3},
+		{name: "Last Stage", elapsed: 68 * 24 * time.Hour, expectedMaxAge: 63072000, expectedStage: 4},
+		{name: "Long After", elapsed: 10 * 365 * 24 * time.Hour, expectedMaxAge: 63072000, expectedStage: 4},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := &fakeClock{scheduleStart.Add(tc.elapsed)}
+			schedule := NewHSTSSchedule(scheduleStart, rampUpStages...)
+			schedule.Clock = clock.Now
+
+			if stage := schedule.current(); stage != tc.expectedStage {
+				t.Errorf("Incorrect stage\tExpected: %d\tActual: %d\n", tc.expectedStage, stage)
+			}
+
+			if maxAge := schedule.Current().MaxAge; maxAge != tc.expectedMaxAge {
+				t.Errorf("Incorrect MaxAge\tExpected: %d\tActual: %d\n", tc.expectedMaxAge, maxAge)
+			}
+		})
+	}
+}
+
+func TestStrictTransportSecurity_Schedule(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{scheduleStart}
+	hsts := NewStrictTransportSecurity(0, true, false)
+	hsts.Schedule = NewHSTSSchedule(scheduleStart, rampUpStages...)
+	hsts.Schedule.Clock = clock.Now
+
+	steps := []struct {
+		advance        time.Duration
+		expectedHeader string
+	}{
+		{advance: 0, expectedHeader: "max-age=300; includeSubDomains"},
+		{advance: time.Hour, expectedHeader: "max-age=300; includeSubDomains"},
+		{advance: 23 * time.Hour, expectedHeader: "max-age=604800; includeSubDomains"},
+		{advance: 7 * 24 * time.Hour, expectedHeader: "max-age=2592000; includeSubDomains"},
+		{advance: 30 * 24 * time.Hour, expectedHeader: "max-age=63072000; includeSubDomains"},
+		{advance: 30 * 24 * time.Hour, expectedHeader: "max-age=63072000; includeSubDomains; preload"},
+	}
+
+	// the steps share a clock, so they must run in order
+	for i, step := range steps {
+		clock.Advance(step.advance)
+
+		if hsts.Empty() {
+			t.Errorf("Step %d: Strict-Transport-Security should not be empty\n", i)
+		}
+
+		header := hsts.String()
+		if header != step.expectedHeader {
+			t.Errorf("Step %d: Expected: %s\tActual: %s\n", i, step.expectedHeader, header)
+		}
+
+		// the cache must have been refreshed for the current stage
+		if hsts.cache != header {
+			t.Errorf("Step %d: cache is stale\tExpected: %s\tActual: %s\n", i, header, hsts.cache)
+		}
+	}
+}
+
+func TestHSTSSchedule_Current_noStages(t *testing.T) {
+	t.Parallel()
+
+	schedule := NewHSTSSchedule(scheduleStart)
+
+	if stage := schedule.Current(); stage != (HSTSStage{}) {
+		t.Errorf("Expected the zero HSTSStage\tActual: %+v\n", stage)
+	}
+
+	hsts := NewStrictTransportSecurity(300, false, false)
+	hsts.Schedule = schedule
+	if header := hsts.String(); header != "max-age=300" {
+		t.Errorf("An empty schedule should fall back to MaxAge\tActual: %s\n", header)
+	}
+}
+
+func TestStrictTransportSecurity_Schedule_concurrent(t *testing.T) {
+	t.Parallel()
+
+	hsts := NewStrictTransportSecurity(0, false, false)
+	hsts.Schedule = NewHSTSSchedule(time.Now(), HSTSStage{MaxAge: 300, Duration: time.Nanosecond}, HSTSStage{MaxAge: 600})
+
+	// run with -race: the stages change while concurrent requests read the cached header
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if header := hsts.String(); header != "max-age=300" && header != "max-age=600" {
+					t.Errorf("Unexpected header: %s\n", header)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,101 @@
+package helmet
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HTTPSRedirect is a middleware that redirects insecure HTTP requests to HTTPS.
+// It decides whether a request is secure the same way its StrictTransportSecurity does, so the two never drift apart.
+type HTTPSRedirect struct {
+	// The Strict-Transport-Security whose trusted proxies are used to detect HTTPS requests.
+	StrictTransportSecurity *StrictTransportSecurity
+
+	// Hosts that may be redirected to, matched like HostAllowlist.Hosts: case-insensitively,
+	// "example.com" on any port, "example.com:8080" only on that (insecure) port, and "*.example.com" for every
+	// subdomain but not example.com itself. Requests for any other host are rejected to prevent open redirects.
+	AllowedHosts []string
+
+	// Maps insecure ports to secure ports, e.g. "8080" to "8443". Unmapped ports are dropped in favour of the default HTTPS port.
+	Ports map[string]string
+
+	// Either http.StatusPermanentRedirect (the default, which preserves the request method) or http.StatusMovedPermanently.
+	StatusCode int
+}
+
+// NewHTTPSRedirect creates a new HTTPSRedirect that shares its proxy trust with the given Strict-Transport-Security.
+func NewHTTPSRedirect(hsts *StrictTransportSecurity, allowedHosts ...string) *HTTPSRedirect {
+	return &HTTPSRedirect{
+		StrictTransportSecurity: hsts,
+		AllowedHosts:            allowedHosts,
+		Ports:                   map[string]string{},
+		StatusCode:              http.StatusPermanentRedirect,
+	}
+}
+
+// Secure is the middleware handler.
+func (hr *HTTPSRedirect) Secure(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hr.trustedProxies().IsHTTPS(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		target, ok := hr.Target(r)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		http.Redirect(w, r, target, hr.statusCode())
+	})
+}
+
+// Target returns the HTTPS URL that the given http.Request is redirected to, and whether its host is allowed.
+func (hr *HTTPSRedirect) Target(r *http.Request) (string, bool) {
+	host, port := splitHostPort(r.Host)
+	if !hr.allowed(host, port) {
+		return "", false
+	}
+
+	if securePort, ok := hr.Ports[port]; ok && securePort != "443" {
+		host = net.JoinHostPort(host, securePort)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	return "https://" + host + r.URL.RequestURI(), true
+}
+
+func (hr *HTTPSRedirect) allowed(host string, port string) bool {
+	for _, allowedHost := range hr.AllowedHosts {
+		if matchHost(allowedHost, host, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func (hr *HTTPSRedirect) trustedProxies() *TrustedProxies {
+	if hr.StrictTransportSecurity == nil {
+		return nil
+	}
+	return hr.StrictTransportSecurity.TrustedProxies
+}
+
+func (hr *HTTPSRedirect) statusCode() int {
+	if hr.StatusCode == http.StatusMovedPermanently {
+		return http.StatusMovedPermanently
+	}
+	return http.StatusPermanentRedirect
+}
+
+// splitHostPort splits a Host HTTP header into its host and (possibly empty) port.
+func splitHostPort(hostport string) (string, string) {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return strings.Trim(hostport, "[]"), ""
+	}
+	return host, port
+}
@@ -0,0 +1,204 @@
+package helmet
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSRedirect_New(t *testing.T) {
+	t.Parallel()
+
+	hsts := NewStrictTransportSecurity(63072000, true, false)
+	hr := NewHTTPSRedirect(hsts, "example.com")
+
+	if hr.StrictTransportSecurity != hsts {
+		t.Errorf("StrictTransportSecurity should be shared\n")
+	}
+
+	if len(hr.AllowedHosts) != 1 || hr.AllowedHosts[0] != "example.com" {
+		t.Errorf("Incorrect AllowedHosts\tExpected: %v\tActual: %v\n", []string{"example.com"}, hr.AllowedHosts)
+	}
+
+	if hr.StatusCode != http.StatusPermanentRedirect {
+		t.Errorf("Incorrect StatusCode\tExpected: %d\tActual: %d\n", http.StatusPermanentRedirect, hr.StatusCode)
+	}
+}
+
+func TestHTTPSRedirect_Target(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		url            string
+		host           string
+		ports          map[string]string
+		expectedTarget string
+		expectedOk     bool
+	}{
+		{name: "Path, Query", url: "/a/b?c=d&e=f", host: "example.com", expectedTarget: "https://example.com/a/b?c=d&e=f", expectedOk: true},
+		{name: "Escaped Path", url: "/a%20b", host: "example.com", expectedTarget: "https://example.com/a%20b", expectedOk: true},
+		{name: "Mixed Case Host", url: "/", host: "EXAMPLE.com", expectedTarget: "https://EXAMPLE.com/", expectedOk: true},
+		{name: "Default Port Dropped", url: "/", host: "example.com:80", expectedTarget: "https://example.com/", expectedOk: true},
+		{name: "Unmapped Port Dropped", url: "/", host: "example.com:8080", expectedTarget: "https://example.com/", expectedOk: true},
+		{
+			name:           "Mapped Port",
+			url:            "/",
+			host:           "example.com:8080",
+			ports:          map[string]string{"8080": "8443"},
+			expectedTarget: "https://example.com:8443/",
+			expectedOk:     true,
+		},
+		{
+			name:           "Mapped To Default Port",
+			url:            "/",
+			host:           "example.com:8080",
+			ports:          map[string]string{"8080": "443"},
+			expectedTarget: "https://example.com/",
+			expectedOk:     true,
+		},
+		{name: "IPv6", url: "/", host: "[::1]:80", expectedTarget: "https://[::1]/", expectedOk: true},
+		{
+			name:           "IPv6, Mapped Port",
+			url:            "/",
+			host:           "[::1]:8080",
+			ports:          map[string]string{"8080": "8443"},
+			expectedTarget: "https://[::1]:8443/",
+			expectedOk:     true,
+		},
+		{name: "Disallowed Host", url: "/", host: "evil.com", expectedOk: false},
+		{name: "Disallowed Subdomain", url: "/", host: "evil.example.com", expectedOk: false},
+		{name: "Empty Host", url: "/", host: "", expectedOk: false},
+		{name: "Wildcard Subdomain", url: "/", host: "a.example.net", expectedTarget: "https://a.example.net/", expectedOk: true},
+		{name: "Wildcard Apex", url: "/", host: "example.net", expectedOk: false},
+		{name: "Port Specific", url: "/", host: "example.org:8080", expectedTarget: "https://example.org/", expectedOk: true},
+		{name: "Port Specific, Other Port", url: "/", host: "example.org:9090", expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hr := NewHTTPSRedirect(nil, "example.com", "::1", "*.example.net", "example.org:8080")
+			if tc.ports != nil {
+				hr.Ports = tc.ports
+			}
+
+			r := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			r.Host = tc.host
+
+			target, ok := hr.Target(r)
+			if ok != tc.expectedOk {
+				t.Errorf("Incorrect ok\tExpected: %t\tActual: %t\n", tc.expectedOk, ok)
+			}
+
+			if target != tc.expectedTarget {
+				t.Errorf("Incorrect target\tExpected: %s\tActual: %s\n", tc.expectedTarget, target)
+			}
+		})
+	}
+}
+
+func TestHTTPSRedirect_Secure(t *testing.T) {
+	t.Parallel()
+
+	trustedProxies, err := NewTrustedProxies("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name             string
+		tls              bool
+		remoteAddr       string
+		host             string
+		headers          map[string]string
+		statusCode       int
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{name: "Direct TLS", tls: true, remoteAddr: "203.0.113.1:1234", host: "example.com", expectedStatus: http.StatusOK},
+		{
+			name:             "Plain HTTP",
+			remoteAddr:       "203.0.113.1:1234",
+			host:             "example.com",
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "https://example.com/path?q=1",
+		},
+		{
+			name:             "Plain HTTP, Moved Permanently",
+			remoteAddr:       "203.0.113.1:1234",
+			host:             "example.com",
+			statusCode:       http.StatusMovedPermanently,
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "https://example.com/path?q=1",
+		},
+		{
+			name:             "Plain HTTP, Unsupported Status Code",
+			remoteAddr:       "203.0.113.1:1234",
+			host:             "example.com",
+			statusCode:       http.StatusFound,
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "https://example.com/path?q=1",
+		},
+		{
+			name:           "Trusted Proxy, HTTPS",
+			remoteAddr:     "10.0.0.1:1234",
+			host:           "example.com",
+			headers:        map[string]string{HeaderXForwardedProto: "https"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "Untrusted Peer, Spoofed HTTPS",
+			remoteAddr:       "203.0.113.1:1234",
+			host:             "example.com",
+			headers:          map[string]string{HeaderXForwardedProto: "https"},
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "https://example.com/path?q=1",
+		},
+		{name: "Open Redirect", remoteAddr: "203.0.113.1:1234", host: "evil.com", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hsts := NewStrictTransportSecurity(63072000, true, false)
+			hsts.TrustedProxies = trustedProxies
+
+			hr := NewHTTPSRedirect(hsts, "example.com")
+			if tc.statusCode != 0 {
+				hr.StatusCode = tc.statusCode
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/path?q=1", nil)
+			r.RemoteAddr = tc.remoteAddr
+			r.Host = tc.host
+			if tc.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+
+			rr := httptest.NewRecorder()
+			hr.Secure(mockNext).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Incorrect status\tExpected: %d\tActual: %d\n", tc.expectedStatus, resp.StatusCode)
+			}
+
+			if location := resp.Header.Get("Location"); location != tc.expectedLocation {
+				t.Errorf("Incorrect Location\tExpected: %s\tActual: %s\n", tc.expectedLocation, location)
+			}
+
+			if tc.expectedStatus == http.StatusOK {
+				testMockNext(t, resp)
+			}
+		})
+	}
+}
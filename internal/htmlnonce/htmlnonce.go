@@ -0,0 +1,380 @@
+// Package htmlnonce adds nonce attributes to the <script> and <style> start tags of an HTML stream.
+//
+// It tokenizes just enough of HTML to find those tags reliably: comments, quoted attribute values and the
+// contents of raw text elements such as <script> itself are skipped, so "<script" inside of them is left alone.
+// The stream is rewritten as it is written, without buffering, so tags may be split across writes.
+package htmlnonce
+
+import (
+	"html"
+	"io"
+)
+
+type state int
+
+const (
+	stateText              state = iota
+	stateTagOpen                 // after "<"
+	stateEndTagOpen              // after "</"
+	stateTagName                 // in the name of a tag
+	stateBeforeAttrName          // between attributes
+	stateAttrName                // in the name of an attribute
+	stateAfterAttrName           // after the name of an attribute, before a possible "="
+	stateBeforeAttrValue         // after "="
+	stateAttrValueQuoted         // in a quoted attribute value
+	stateAttrValueUnquoted       // in an unquoted attribute value
+	stateMarkupOpen              // after "<!"
+	stateCommentOpen             // after "<!-"
+	stateCommentStart            // after "<!--"
+	stateCommentStartDash        // after "<!---"
+	stateComment                 // in a comment
+	stateCommentEndDash          // after "-" in a comment
+	stateCommentEnd              // after "--" in a comment
+	stateCommentEndBang          // after "--!" in a comment
+	stateBogusComment            // in "<!...>" or "<?...>"
+	stateRawText                 // in the contents of a raw text element, e.g. <script>
+)
+
+// maxNameLength caps how much of a tag or attribute name is kept, longer names are never interesting.
+const maxNameLength = 16
+
+// rawTextElements are the elements whose contents are never parsed as tags.
+var rawTextElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"textarea": true,
+	"title":    true,
+	"xmp":      true,
+	"iframe":   true,
+	"noembed":  true,
+	"noframes": true,
+}
+
+// Writer adds a nonce attribute to every <script> and <style> start tag written to it, unless it already has one.
+type Writer struct {
+	w    io.Writer
+	attr []byte // the nonce attribute to add, with a leading space
+
+	state    state
+	endTag   bool   // whether the current tag is an end tag
+	tagName  []byte // lower-cased name of the current tag
+	attrName []byte // lower-cased name of the current attribute
+	hasNonce bool   // whether the current tag already has a nonce attribute
+	quote    byte   // quote of the current attribute value
+	rawText  string // name of the raw text element whose contents are being written
+	matched  int    // bytes of the raw text element's end tag matched so far
+
+	out []byte
+}
+
+// NewWriter creates a new Writer that writes to w, adding the given nonce.
+func NewWriter(w io.Writer, nonce string) *Writer {
+	return &Writer{
+		w:    w,
+		attr: []byte(` nonce="` + html.EscapeString(nonce) + `"`),
+	}
+}
+
+// Write rewrites p and writes it to the underlying io.Writer. It reports len(p) if everything was written.
+func (nw *Writer) Write(p []byte) (int, error) {
+	nw.out = nw.out[:0]
+	for _, c := range p {
+		nw.step(c)
+	}
+
+	if _, err := nw.w.Write(nw.out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// step advances the tokenizer by one byte, and appends it to the output along with any nonce attribute.
+func (nw *Writer) step(c byte) {
+	switch nw.state {
+	case stateText:
+		if c == '<' {
+			nw.state = stateTagOpen
+		}
+
+	case stateTagOpen:
+		switch {
+		case isASCIILetter(c):
+			nw.startTag(c, false)
+		case c == '/':
+			nw.state = stateEndTagOpen
+		case c == '!':
+			nw.state = stateMarkupOpen
+		case c == '?':
+			nw.state = stateBogusComment
+		default:
+			nw.state = stateText
+			nw.step(c)
+			return
+		}
+
+	case stateEndTagOpen:
+		switch {
+		case isASCIILetter(c):
+			nw.startTag(c, true)
+		case c == '>':
+			nw.state = stateText
+		default:
+			nw.state = stateBogusComment
+		}
+
+	case stateTagName:
+		switch {
+		case isSpace(c), c == '/':
+			nw.state = stateBeforeAttrName
+		case c == '>':
+			nw.finishTag()
+		default:
+			nw.tagName = appendLower(nw.tagName, c)
+		}
+
+	case stateBeforeAttrName:
+		switch {
+		case isSpace(c), c == '/':
+		case c == '>':
+			nw.finishTag()
+		default:
+			nw.attrName = appendLower(nw.attrName[:0], c)
+			nw.state = stateAttrName
+		}
+
+	case stateAttrName:
+		switch {
+		case isSpace(c):
+			nw.finishAttrName()
+			nw.state = stateAfterAttrName
+		case c == '/':
+			nw.finishAttrName()
+			nw.state = stateBeforeAttrName
+		case c == '=':
+			nw.finishAttrName()
+			nw.state = stateBeforeAttrValue
+		case c == '>':
+			nw.finishAttrName()
+			nw.finishTag()
+		default:
+			nw.attrName = appendLower(nw.attrName, c)
+		}
+
+	case stateAfterAttrName:
+		switch {
+		case isSpace(c):
+		case c == '/':
+			nw.state = stateBeforeAttrName
+		case c == '=':
+			nw.state = stateBeforeAttrValue
+		case c == '>':
+			nw.finishTag()
+		default:
+			nw.attrName = appendLower(nw.attrName[:0], c)
+			nw.state = stateAttrName
+		}
+
+	case stateBeforeAttrValue:
+		switch {
+		case isSpace(c):
+		case c == '"', c == '\'':
+			nw.quote = c
+			nw.state = stateAttrValueQuoted
+		case c == '>':
+			nw.finishTag()
+		default:
+			nw.state = stateAttrValueUnquoted
+		}
+
+	case stateAttrValueQuoted:
+		if c == nw.quote {
+			nw.state = stateBeforeAttrName
+		}
+
+	case stateAttrValueUnquoted:
+		switch {
+		case isSpace(c):
+			nw.state = stateBeforeAttrName
+		case c == '>':
+			nw.finishTag()
+		}
+
+	case stateMarkupOpen:
+		switch c {
+		case '-':
+			nw.state = stateCommentOpen
+		case '>':
+			nw.state = stateText
+		default:
+			nw.state = stateBogusComment
+		}
+
+	case stateCommentOpen:
+		switch c {
+		case '-':
+			nw.state = stateCommentStart
+		case '>':
+			nw.state = stateText
+		default:
+			nw.state = stateBogusComment
+		}
+
+	// the comment states follow the HTML standard, including the abrupt endings "<!-->" and "<!--->",
+	// and "--!>", which browsers accept as the end of a comment
+	case stateCommentStart:
+		switch c {
+		case '-':
+			nw.state = stateCommentStartDash
+		case '>':
+			nw.state = stateText
+		default:
+			nw.state = stateComment
+		}
+
+	case stateCommentStartDash:
+		switch c {
+		case '-':
+			nw.state = stateCommentEnd
+		case '>':
+			nw.state = stateText
+		default:
+			nw.state = stateComment
+		}
+
+	case stateComment:
+		if c == '-' {
+			nw.state = stateCommentEndDash
+		}
+
+	case stateCommentEndDash:
+		if c == '-' {
+			nw.state = stateCommentEnd
+		} else {
+			nw.state = stateComment
+		}
+
+	case stateCommentEnd:
+		switch c {
+		case '>':
+			nw.state = stateText
+		case '!':
+			nw.state = stateCommentEndBang
+		case '-':
+		default:
+			nw.state = stateComment
+		}
+
+	case stateCommentEndBang:
+		switch c {
+		case '-':
+			nw.state = stateCommentEndDash
+		case '>':
+			nw.state = stateText
+		default:
+			nw.state = stateComment
+		}
+
+	case stateBogusComment:
+		if c == '>' {
+			nw.state = stateText
+		}
+
+	case stateRawText:
+		nw.stepRawText(c)
+		return
+	}
+
+	nw.out = append(nw.out, c)
+}
+
+// stepRawText looks for the end tag of the current raw text element, e.g. "</script".
+func (nw *Writer) stepRawText(c byte) {
+	switch {
+	case nw.matched == 0:
+		if c == '<' {
+			nw.matched = 1
+		}
+	case nw.matched == 1:
+		if c == '/' {
+			nw.matched = 2
+		} else {
+			nw.matched = 0
+			nw.stepRawText(c)
+			return
+		}
+	case nw.matched < len(nw.rawText)+2:
+		if toLower(c) == nw.rawText[nw.matched-2] {
+			nw.matched++
+		} else {
+			nw.matched = 0
+			nw.stepRawText(c)
+			return
+		}
+	default:
+		// the full name matched, it is only the end tag if the name ends here
+		nw.matched = 0
+		if isSpace(c) || c == '/' || c == '>' {
+			nw.tagName = append(nw.tagName[:0], nw.rawText...)
+			nw.endTag = true
+			nw.state = stateTagName
+			nw.step(c)
+			return
+		}
+		nw.stepRawText(c)
+		return
+	}
+
+	nw.out = append(nw.out, c)
+}
+
+func (nw *Writer) startTag(c byte, endTag bool) {
+	nw.endTag = endTag
+	nw.hasNonce = false
+	nw.tagName = appendLower(nw.tagName[:0], c)
+	nw.state = stateTagName
+}
+
+func (nw *Writer) finishAttrName() {
+	if string(nw.attrName) == "nonce" {
+		nw.hasNonce = true
+	}
+}
+
+// finishTag is called on the ">" that ends a tag, before it is appended to the output.
+func (nw *Writer) finishTag() {
+	name := string(nw.tagName)
+
+	if !nw.endTag && !nw.hasNonce && (name == "script" || name == "style") {
+		nw.out = append(nw.out, nw.attr...)
+	}
+
+	if !nw.endTag && rawTextElements[name] {
+		nw.rawText = name
+		nw.matched = 0
+		nw.state = stateRawText
+		return
+	}
+	nw.state = stateText
+}
+
+func appendLower(b []byte, c byte) []byte {
+	if len(b) >= maxNameLength {
+		return b
+	}
+	return append(b, toLower(c))
+}
+
+func toLower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
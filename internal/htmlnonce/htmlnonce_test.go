@@ -0,0 +1,121 @@
+package htmlnonce
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "No Tags", input: "hello world", expected: "hello world"},
+		{name: "Script", input: `<script>alert(1)</script>`, expected: `<script nonce="abc">alert(1)</script>`},
+		{name: "Style", input: `<style>p{}</style>`, expected: `<style nonce="abc">p{}</style>`},
+		{name: "Upper Case", input: `<SCRIPT>x</SCRIPT>`, expected: `<SCRIPT nonce="abc">x</SCRIPT>`},
+		{
+			name:     "Attributes",
+			input:    `<script src="/app.js" defer type=module></script>`,
+			expected: `<script src="/app.js" defer type=module nonce="abc"></script>`,
+		},
+		{name: "Self Closing", input: `<script src=/a.js />`, expected: `<script src=/a.js / nonce="abc">`},
+		{name: "Existing Nonce", input: `<script NONCE="xyz">x</script>`, expected: `<script NONCE="xyz">x</script>`},
+		{name: "Other Tags", input: `<p class="script"><scripts><a href=x>y</a>`, expected: `<p class="script"><scripts><a href=x>y</a>`},
+		{
+			name:     "Quoted Greater Than",
+			input:    `<script data-x="a>b" data-y='<script>'>x</script>`,
+			expected: `<script data-x="a>b" data-y='<script>' nonce="abc">x</script>`,
+		},
+		{
+			name:     "Script In Attribute Of Other Tag",
+			input:    `<div title="<script>"></div>`,
+			expected: `<div title="<script>"></div>`,
+		},
+		{
+			name:     "Script Contents",
+			input:    `<script>document.write("<style>"); if (a</scriptx) {}</script><style>`,
+			expected: `<script nonce="abc">document.write("<style>"); if (a</scriptx) {}</script><style nonce="abc">`,
+		},
+		{name: "Comment", input: `<!-- <script> --><script>`, expected: `<!-- <script> --><script nonce="abc">`},
+		{name: "Comment With Dashes", input: `<!-- a -- <script> ---><script>`, expected: `<!-- a -- <script> ---><script nonce="abc">`},
+		{name: "Abrupt Empty Comment", input: `<!--><script>`, expected: `<!--><script nonce="abc">`},
+		{name: "Abrupt Dash Comment", input: `<!---><script>`, expected: `<!---><script nonce="abc">`},
+		{name: "Comment Ended With Bang", input: `<!-- <script> --!><script>`, expected: `<!-- <script> --!><script nonce="abc">`},
+		{name: "Comment With Bang", input: `<!-- --! <script> --><script>`, expected: `<!-- --! <script> --><script nonce="abc">`},
+		{name: "Comment Ending With Many Dashes", input: `<!-- a ----><script>`, expected: `<!-- a ----><script nonce="abc">`},
+		{name: "Doctype", input: `<!DOCTYPE html><script>`, expected: `<!DOCTYPE html><script nonce="abc">`},
+		{name: "Textarea", input: `<textarea><script></textarea><script>`, expected: `<textarea><script></textarea><script nonce="abc">`},
+		{name: "Literal Less Than", input: `a < b <script>`, expected: `a < b <script nonce="abc">`},
+		{name: "End Tag Outside Raw Text", input: `</script><script>`, expected: `</script><script nonce="abc">`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// every way to split the input in two writes must give the same output
+			for i := 0; i <= len(tc.input); i++ {
+				var buf bytes.Buffer
+				nw := NewWriter(&buf, "abc")
+
+				for _, chunk := range []string{tc.input[:i], tc.input[i:]} {
+					n, err := nw.Write([]byte(chunk))
+					if err != nil || n != len(chunk) {
+						t.Fatalf("Incorrect write\tExpected: %d\tActual: %d, %v\n", len(chunk), n, err)
+					}
+				}
+
+				if buf.String() != tc.expected {
+					t.Fatalf("Split at %d\tExpected: %s\tActual: %s\n", i, tc.expected, buf.String())
+				}
+			}
+
+			// as well as writing it byte by byte
+			var buf bytes.Buffer
+			nw := NewWriter(&buf, "abc")
+			for i := 0; i < len(tc.input); i++ {
+				if _, err := nw.Write([]byte{tc.input[i]}); err != nil {
+					t.Fatalf("Unexpected error: %v\n", err)
+				}
+			}
+
+			if buf.String() != tc.expected {
+				t.Errorf("Byte by byte\tExpected: %s\tActual: %s\n", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestWriter_escapesNonce(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if _, err := NewWriter(&buf, `"><b>`).Write([]byte("<script>")); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	expected := `<script nonce="&#34;&gt;&lt;b&gt;">`
+	if buf.String() != expected {
+		t.Errorf("Expected: %s\tActual: %s\n", expected, buf.String())
+	}
+}
+
+type errorWriter struct{}
+
+func (errorWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken")
+}
+
+func TestWriter_error(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewWriter(errorWriter{}, "abc").Write([]byte("<script>")); err == nil {
+		t.Errorf("Errors of the underlying io.Writer should be returned\n")
+	}
+}
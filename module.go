@@ -0,0 +1,65 @@
+package helmet
+
+import "net/http"
+
+// Module represents an HTTP security header that Helmet sets on every response.
+type Module interface {
+	// Name returns the name of the Module, usually the HTTP header it sets.
+	Name() string
+
+	// Empty returns whether the Module has nothing to set.
+	Empty() bool
+
+	// Header adds the Module's HTTP header to the given http.ResponseWriter.
+	Header(w http.ResponseWriter, r *http.Request)
+}
+
+// headerer is implemented by every built-in HTTP security header.
+type headerer interface {
+	Empty() bool
+	HeaderFor(w http.ResponseWriter, r *http.Request)
+}
+
+// builtinModule adapts a built-in HTTP security header to the Module interface.
+type builtinModule struct {
+	name     string
+	headerer headerer
+}
+
+func (m builtinModule) Name() string {
+	return m.name
+}
+
+func (m builtinModule) Empty() bool {
+	return m.headerer.Empty()
+}
+
+func (m builtinModule) Header(w http.ResponseWriter, r *http.Request) {
+	m.headerer.HeaderFor(w, r)
+}
+
+func (m builtinModule) Deprecation() *Deprecation {
+	if deprecated, ok := m.headerer.(DeprecatedModule); ok {
+		return deprecated.Deprecation()
+	}
+	return nil
+}
+
+// ResponseWrapper is implemented by Modules that need to inspect or rewrite the response written by the next http.Handler.
+// If the wrapped http.ResponseWriter implements io.Closer, it is closed once the next http.Handler returns.
+type ResponseWrapper interface {
+	WrapResponseWriter(w http.ResponseWriter, r *http.Request) http.ResponseWriter
+}
+
+// RequestFilter is implemented by Modules that can reject a request before it reaches the next http.Handler.
+// Every RequestFilter runs before CORS answers a preflight request.
+type RequestFilter interface {
+	// Allow returns whether the request may continue. When it returns false it has already written the response.
+	Allow(w http.ResponseWriter, r *http.Request) bool
+}
+
+// requestAnswerer is implemented by RequestFilters that answer some requests themselves, rather than rejecting them.
+// Helmet runs them after every other RequestFilter.
+type requestAnswerer interface {
+	answersRequests()
+}
@@ -0,0 +1,88 @@
+package helmet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockModule is a custom Module that sets a fixed header value.
+type mockModule struct {
+	name  string
+	value string
+}
+
+func (m mockModule) Name() string {
+	return m.name
+}
+
+func (m mockModule) Empty() bool {
+	return m.value == ""
+}
+
+func (m mockModule) Header(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(m.name, m.value)
+}
+
+func TestBuiltinModule(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		module         builtinModule
+		expectedEmpty  bool
+		expectedHeader string
+	}{
+		{
+			name:           "Empty",
+			module:         builtinModule{HeaderXFrameOptions, XFrameOptions("")},
+			expectedEmpty:  true,
+			expectedHeader: "",
+		},
+		{
+			name:           "Value Type",
+			module:         builtinModule{HeaderXFrameOptions, XFrameOptionsDeny},
+			expectedEmpty:  false,
+			expectedHeader: "DENY",
+		},
+		{
+			name:           "Pointer Type",
+			module:         builtinModule{HeaderStrictTransportSecurity, NewStrictTransportSecurity(63072000, false, false)},
+			expectedEmpty:  false,
+			expectedHeader: "max-age=63072000",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr, r := newRecorderRequest(t)
+
+			if empty := tc.module.Empty(); empty != tc.expectedEmpty {
+				t.Errorf("Incorrect Empty\tExpected: %t\tActual: %t\n", tc.expectedEmpty, empty)
+			}
+
+			tc.module.Header(rr, r)
+			if header := rr.Result().Header.Get(tc.module.Name()); header != tc.expectedHeader {
+				t.Errorf("Incorrect Header\tExpected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+}
+
+func TestBuiltinModule_compatibility(t *testing.T) {
+	t.Parallel()
+
+	headerRR, forRR := httptest.NewRecorder(), httptest.NewRecorder()
+	hsts := NewStrictTransportSecurity(63072000, true, false)
+
+	hsts.Header(headerRR)
+	hsts.HeaderFor(forRR, httptest.NewRequest(http.MethodGet, "https://example.com/", nil))
+
+	header, headerFor := headerRR.Result().Header.Get(HeaderStrictTransportSecurity), forRR.Result().Header.Get(HeaderStrictTransportSecurity)
+	if header != headerFor {
+		t.Errorf("Header and HeaderFor should match\tHeader: %s\tHeaderFor: %s\n", header, headerFor)
+	}
+}
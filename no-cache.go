@@ -0,0 +1,88 @@
+package helmet
+
+import "net/http"
+
+// List of all HTTP caching headers.
+const (
+	HeaderCacheControl = "Cache-Control"
+	HeaderPragma       = "Pragma"
+	HeaderExpires      = "Expires"
+)
+
+// NoCacheCacheControl is the Cache-Control value that prevents sensitive responses from being stored.
+const NoCacheCacheControl = "no-store, max-age=0"
+
+// NoCache prevents browsers and shared caches from storing sensitive responses, such as authenticated pages.
+// It only applies to requests that match one of its conditions.
+type NoCache struct {
+	// Whether requests with an Authorization HTTP header are sensitive.
+	Authorization bool
+
+	// Names of cookies that make a request sensitive when present, such as a session cookie.
+	SessionCookies []string
+
+	// Paths that are always sensitive. A path ending in a slash matches every path below it, so "/" matches everything.
+	Paths []string
+
+	// Whether to replace a Cache-Control HTTP header that the http.Handler has set itself.
+	Override bool
+}
+
+// NewNoCache creates a new NoCache that applies to requests with an Authorization HTTP header or one of the given session cookies.
+func NewNoCache(sessionCookies ...string) *NoCache {
+	return &NoCache{
+		Authorization:  true,
+		SessionCookies: sessionCookies,
+	}
+}
+
+// EmptyNoCache creates a blank slate NoCache.
+func EmptyNoCache() *NoCache {
+	return &NoCache{}
+}
+
+// Name returns the Cache-Control HTTP header.
+func (nc *NoCache) Name() string {
+	return HeaderCacheControl
+}
+
+// Empty returns whether the NoCache is empty.
+func (nc *NoCache) Empty() bool {
+	return !nc.Authorization && len(nc.SessionCookies) == 0 && len(nc.Paths) == 0
+}
+
+// Header does nothing, since the http.Handler's own Cache-Control HTTP header is only known once it writes the response.
+func (nc *NoCache) Header(w http.ResponseWriter, r *http.Request) {}
+
+// WrapResponseWriter returns an http.ResponseWriter that adds the caching HTTP headers right before they are written,
+// if the http.Request is sensitive.
+func (nc *NoCache) WrapResponseWriter(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if !nc.Sensitive(r) {
+		return w
+	}
+
+	return newHookedResponseWriter(w, func() {
+		if w.Header().Get(HeaderCacheControl) != "" && !nc.Override {
+			return
+		}
+
+		w.Header().Set(HeaderCacheControl, NoCacheCacheControl)
+		w.Header().Set(HeaderPragma, "no-cache")
+		w.Header().Set(HeaderExpires, "0")
+	})
+}
+
+// Sensitive returns whether the http.Request matches any of the NoCache's conditions.
+func (nc *NoCache) Sensitive(r *http.Request) bool {
+	if nc.Authorization && r.Header.Get("Authorization") != "" {
+		return true
+	}
+
+	for _, name := range nc.SessionCookies {
+		if _, err := r.Cookie(name); err == nil {
+			return true
+		}
+	}
+
+	return matchPath(nc.Paths, r.URL.Path)
+}
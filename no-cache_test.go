@@ -0,0 +1,162 @@
+package helmet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNoCache_Empty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		noCache       *NoCache
+		expectedEmpty bool
+	}{
+		{name: "Empty", noCache: EmptyNoCache(), expectedEmpty: true},
+		{name: "Authorization", noCache: NewNoCache(), expectedEmpty: false},
+		{name: "Session Cookies", noCache: &NoCache{SessionCookies: []string{"session"}}, expectedEmpty: false},
+		{name: "Paths", noCache: &NoCache{Paths: []string{"/account/"}}, expectedEmpty: false},
+		{name: "Override Only", noCache: &NoCache{Override: true}, expectedEmpty: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.noCache.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
+
+func TestNoCache_Sensitive(t *testing.T) {
+	t.Parallel()
+
+	noCache := NewNoCache("session")
+	noCache.Paths = []string{"/account/", "/checkout"}
+
+	testCases := []struct {
+		name              string
+		path              string
+		headers           map[string]string
+		expectedSensitive bool
+	}{
+		{name: "Anonymous", path: "/", expectedSensitive: false},
+		{name: "Authorization", path: "/", headers: map[string]string{"Authorization": "Bearer token"}, expectedSensitive: true},
+		{name: "Session Cookie", path: "/", headers: map[string]string{"Cookie": "theme=dark; session=abc"}, expectedSensitive: true},
+		{name: "Other Cookie", path: "/", headers: map[string]string{"Cookie": "theme=dark"}, expectedSensitive: false},
+		{name: "Path Prefix", path: "/account/settings", expectedSensitive: true},
+		{name: "Exact Path", path: "/checkout", expectedSensitive: true},
+		{name: "Exact Path Is Not A Prefix", path: "/checkout/done", expectedSensitive: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+
+			sensitive := noCache.Sensitive(r)
+			if sensitive != tc.expectedSensitive {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedSensitive, sensitive)
+			}
+		})
+	}
+}
+
+func TestHelmet_Secure_noCache(t *testing.T) {
+	t.Parallel()
+
+	explicitCaching := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderCacheControl, "public, max-age=3600")
+		w.Write([]byte("OK"))
+	})
+
+	testCases := []struct {
+		name                 string
+		authorization        string
+		override             bool
+		handler              http.Handler
+		expectedCacheControl string
+		expectedPragma       string
+		expectedExpires      string
+	}{
+		{
+			name:                 "Not Sensitive",
+			handler:              mockNext,
+			expectedCacheControl: "",
+		},
+		{
+			name:                 "Sensitive",
+			authorization:        "Bearer token",
+			handler:              mockNext,
+			expectedCacheControl: NoCacheCacheControl,
+			expectedPragma:       "no-cache",
+			expectedExpires:      "0",
+		},
+		{
+			name:                 "Sensitive, Handler Sets Cache-Control",
+			authorization:        "Bearer token",
+			handler:              explicitCaching,
+			expectedCacheControl: "public, max-age=3600",
+		},
+		{
+			name:                 "Sensitive, Handler Sets Cache-Control, Override",
+			authorization:        "Bearer token",
+			override:             true,
+			handler:              explicitCaching,
+			expectedCacheControl: NoCacheCacheControl,
+			expectedPragma:       "no-cache",
+			expectedExpires:      "0",
+		},
+		{
+			name:                 "Sensitive, Handler Never Writes",
+			authorization:        "Bearer token",
+			handler:              http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			expectedCacheControl: NoCacheCacheControl,
+			expectedPragma:       "no-cache",
+			expectedExpires:      "0",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr, r := newRecorderRequest(t)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+
+			noCache := NewNoCache()
+			noCache.Override = tc.override
+
+			helmet := Empty()
+			helmet.Use(noCache)
+			helmet.Secure(tc.handler).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if header := resp.Header.Get(HeaderCacheControl); header != tc.expectedCacheControl {
+				t.Errorf("Incorrect Cache-Control\tExpected: %s\tActual: %s\n", tc.expectedCacheControl, header)
+			}
+
+			if header := resp.Header.Get(HeaderPragma); header != tc.expectedPragma {
+				t.Errorf("Incorrect Pragma\tExpected: %s\tActual: %s\n", tc.expectedPragma, header)
+			}
+
+			if header := resp.Header.Get(HeaderExpires); header != tc.expectedExpires {
+				t.Errorf("Incorrect Expires\tExpected: %s\tActual: %s\n", tc.expectedExpires, header)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package helmet
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+
+	"github.com/goddtriffin/helmet/internal/htmlnonce"
+)
+
+// List of HTTP headers that decide whether a response body can be rewritten, or that describe the body as it was.
+const (
+	HeaderContentType     = "Content-Type"
+	HeaderContentLength   = "Content-Length"
+	HeaderContentEncoding = "Content-Encoding"
+	HeaderETag            = "ETag"
+	HeaderLastModified    = "Last-Modified"
+	HeaderAcceptRanges    = "Accept-Ranges"
+)
+
+// ModuleNonceInjector is the name of the NonceInjector Module.
+const ModuleNonceInjector = "Nonce-Injector"
+
+// NonceInjector adds the per-request Content-Security-Policy nonce to every <script> and <style> tag of text/html
+// responses, for handlers that can't add it themselves. It only does anything once ContentSecurityPolicy.AddNonce
+// was called, and has to be enabled explicitly with Helmet.Use.
+//
+// The response is rewritten as it is written, without buffering it, so tags may be split across writes.
+// Responses that aren't text/html, that are already compressed, or that are partial (206) or not modified (304),
+// are passed through untouched, and so are hijacked connections. Rewritten responses lose the validators and
+// range support of the original body, since it no longer matches them.
+//
+// Every <script> tag of the response gets the nonce, including one that an attacker managed to inject into it,
+// so the nonce doesn't protect the handlers it is injected for against XSS. It only lets their scripts run under
+// a Content-Security-Policy that requires nonces, while handlers that add the nonce themselves stay protected.
+type NonceInjector struct{}
+
+// NewNonceInjector creates a new NonceInjector.
+func NewNonceInjector() *NonceInjector {
+	return &NonceInjector{}
+}
+
+// Name returns the name of the NonceInjector Module.
+func (ni *NonceInjector) Name() string {
+	return ModuleNonceInjector
+}
+
+// Empty returns false, the NonceInjector is enabled by using it.
+func (ni *NonceInjector) Empty() bool {
+	return false
+}
+
+// Header does nothing, since NonceInjector only rewrites the response body.
+func (ni *NonceInjector) Header(w http.ResponseWriter, r *http.Request) {}
+
+// WrapResponseWriter returns an http.ResponseWriter that adds the nonce of the http.Request to the response body,
+// if it turns out to be HTML. HEAD requests and requests without a nonce are left alone.
+func (ni *NonceInjector) WrapResponseWriter(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	nonce := CSPNonce(r.Context())
+	if nonce == "" || r.Method == http.MethodHead {
+		return w
+	}
+
+	return withOptionalInterfaces(&nonceResponseWriter{ResponseWriter: w, nonce: nonce})
+}
+
+// nonceResponseWriter decides whether to rewrite the response once the http.Handler starts writing its body,
+// since the Content-Type may only be known by sniffing it. Until then, the status code is held back, so that
+// the Content-Length can still be removed.
+type nonceResponseWriter struct {
+	http.ResponseWriter
+
+	nonce      string
+	statusCode int
+	decided    bool
+	body       io.Writer // the underlying http.ResponseWriter, or an htmlnonce.Writer wrapping it
+}
+
+// decide picks how the body is written based on the response headers, or the first bytes of the body if the
+// Content-Type isn't set, then writes the held back status code.
+func (nw *nonceResponseWriter) decide(p []byte) {
+	if nw.decided {
+		return
+	}
+	nw.decided = true
+	nw.body = nw.ResponseWriter
+
+	header := nw.Header()
+	contentType := header.Get(HeaderContentType)
+	if contentType == "" && len(p) > 0 {
+		// sniff the same way net/http would, and set it, so it isn't sniffed again from the rewritten body
+		contentType = http.DetectContentType(p)
+		header.Set(HeaderContentType, contentType)
+	}
+
+	// partial and not modified responses refer to the original body, which the client may already have
+	rewritable := nw.statusCode != http.StatusPartialContent && nw.statusCode != http.StatusNotModified
+
+	if rewritable && isHTMLContentType(contentType) && header.Get(HeaderContentEncoding) == "" {
+		// every inserted nonce makes the body longer than announced, and different from what the validators
+		// and ranges describe
+		header.Del(HeaderContentLength)
+		header.Del(HeaderETag)
+		header.Del(HeaderLastModified)
+		header.Del(HeaderAcceptRanges)
+		nw.body = htmlnonce.NewWriter(nw.ResponseWriter, nw.nonce)
+	}
+
+	if nw.statusCode != 0 {
+		nw.ResponseWriter.WriteHeader(nw.statusCode)
+	}
+}
+
+// WriteHeader holds back the status code until the first write, unless it is informational.
+func (nw *nonceResponseWriter) WriteHeader(statusCode int) {
+	if nw.decided || statusCode < http.StatusOK {
+		nw.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	if nw.statusCode == 0 {
+		nw.statusCode = statusCode
+	}
+}
+
+// Write writes the response body, adding the nonce if it is HTML.
+func (nw *nonceResponseWriter) Write(b []byte) (int, error) {
+	nw.decide(b)
+	return nw.body.Write(b)
+}
+
+// flush writes the response headers and what was written so far, since the decision can't be held back any longer.
+func (nw *nonceResponseWriter) flush() {
+	nw.decide(nil)
+	nw.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijack hands the connection over without rewriting anything, since the http.Handler writes to it directly.
+func (nw *nonceResponseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	nw.decided = true
+	nw.body = nw.ResponseWriter
+	return nw.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Close writes the held back status code if the http.Handler never wrote a body.
+func (nw *nonceResponseWriter) Close() error {
+	nw.decide(nil)
+	return nil
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (nw *nonceResponseWriter) Unwrap() http.ResponseWriter {
+	return nw.ResponseWriter
+}
+
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/html"
+}
@@ -0,0 +1,300 @@
+package helmet
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestNonceInjector(t *testing.T) {
+	t.Parallel()
+
+	// writes the body in chunks, splitting it in the middle of tags
+	writeChunks := func(headers map[string]string, statusCode int, chunks ...string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, value := range headers {
+				w.Header().Set(name, value)
+			}
+			if statusCode != 0 {
+				w.WriteHeader(statusCode)
+			}
+			for _, chunk := range chunks {
+				w.Write([]byte(chunk))
+			}
+		})
+	}
+
+	testCases := []struct {
+		name                  string
+		method                string
+		withoutNonce          bool
+		handler               http.Handler
+		expectedStatusCode    int
+		expectedBody          string // with NONCE in place of the nonce
+		expectedContentType   string
+		expectedContentLength string
+	}{
+		{
+			name:                "HTML",
+			handler:             writeChunks(map[string]string{HeaderContentType: "text/html; charset=utf-8"}, 0, "<p>hi</p><scr", "ipt src=/a.js", "></script><style>p{}</style>"),
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        `<p>hi</p><script src=/a.js nonce="NONCE"></script><style nonce="NONCE">p{}</style>`,
+			expectedContentType: "text/html; charset=utf-8",
+		},
+		{
+			name:                "HTML, Content-Length Removed",
+			handler:             writeChunks(map[string]string{HeaderContentType: "text/html", HeaderContentLength: "17"}, http.StatusCreated, "<script></script>"),
+			expectedStatusCode:  http.StatusCreated,
+			expectedBody:        `<script nonce="NONCE"></script>`,
+			expectedContentType: "text/html",
+		},
+		{
+			name:                "Sniffed HTML",
+			handler:             writeChunks(nil, http.StatusNotFound, "<!DOCTYPE html><script>", "</script>"),
+			expectedStatusCode:  http.StatusNotFound,
+			expectedBody:        `<!DOCTYPE html><script nonce="NONCE"></script>`,
+			expectedContentType: "text/html; charset=utf-8",
+		},
+		{
+			name:                  "JSON",
+			handler:               writeChunks(map[string]string{HeaderContentType: "application/json", HeaderContentLength: "19"}, 0, `{"a": "<script>"}`),
+			expectedStatusCode:    http.StatusOK,
+			expectedBody:          `{"a": "<script>"}`,
+			expectedContentType:   "application/json",
+			expectedContentLength: "19",
+		},
+		{
+			name:                "Sniffed Plain Text",
+			handler:             writeChunks(nil, 0, "a <script> tag"),
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        "a <script> tag",
+			expectedContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:                "Compressed HTML",
+			handler:             writeChunks(map[string]string{HeaderContentType: "text/html", HeaderContentEncoding: "gzip"}, 0, "<script>"),
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        "<script>",
+			expectedContentType: "text/html",
+		},
+		{
+			name:                "Without Nonce",
+			withoutNonce:        true,
+			handler:             writeChunks(map[string]string{HeaderContentType: "text/html"}, 0, "<script>"),
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        "<script>",
+			expectedContentType: "text/html",
+		},
+		{
+			name:                  "HEAD",
+			method:                http.MethodHead,
+			handler:               writeChunks(map[string]string{HeaderContentType: "text/html", HeaderContentLength: "8"}, 0),
+			expectedStatusCode:    http.StatusOK,
+			expectedContentType:   "text/html",
+			expectedContentLength: "8",
+		},
+		{
+			name:               "No Body",
+			handler:            writeChunks(nil, http.StatusNoContent),
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			helmet := Empty()
+			if !tc.withoutNonce {
+				helmet.ContentSecurityPolicy.AddNonce(DirectiveScriptSrc)
+			}
+			helmet.Use(NewNonceInjector())
+
+			var nonce string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nonce = CSPNonce(r.Context())
+				tc.handler.ServeHTTP(w, r)
+			})
+
+			rr, r := newRecorderRequest(t)
+			if tc.method != "" {
+				r.Method = tc.method
+			}
+			helmet.Secure(handler).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Errorf("Incorrect status code\tExpected: %d\tActual: %d\n", tc.expectedStatusCode, resp.StatusCode)
+			}
+
+			expectedBody := strings.ReplaceAll(tc.expectedBody, "NONCE", nonce)
+			if body := rr.Body.String(); body != expectedBody {
+				t.Errorf("Incorrect body\tExpected: %s\tActual: %s\n", expectedBody, body)
+			}
+
+			if header := resp.Header.Get(HeaderContentType); header != tc.expectedContentType {
+				t.Errorf("Incorrect Content-Type\tExpected: %s\tActual: %s\n", tc.expectedContentType, header)
+			}
+
+			if header := resp.Header.Get(HeaderContentLength); header != tc.expectedContentLength {
+				t.Errorf("Incorrect Content-Length\tExpected: %s\tActual: %s\n", tc.expectedContentLength, header)
+			}
+		})
+	}
+}
+
+func TestNonceInjector_fileServer(t *testing.T) {
+	t.Parallel()
+
+	const page = "<script>a()</script>"
+	modTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	files := fstest.MapFS{"page.html": {Data: []byte(page), ModTime: modTime}}
+
+	testCases := []struct {
+		name               string
+		headers            map[string]string
+		expectedStatusCode int
+		expectedBody       string // with NONCE in place of the nonce
+		expectedHeaders    map[string]string
+	}{
+		{
+			name:               "Full",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `<script nonce="NONCE">a()</script>`,
+			expectedHeaders:    map[string]string{HeaderContentLength: "", HeaderLastModified: "", HeaderAcceptRanges: ""},
+		},
+		{
+			name:               "Range",
+			headers:            map[string]string{"Range": "bytes=0-6"},
+			expectedStatusCode: http.StatusPartialContent,
+			expectedBody:       "<script",
+			expectedHeaders:    map[string]string{HeaderContentLength: "7", HeaderLastModified: modTime.Format(http.TimeFormat)},
+		},
+		{
+			name:               "Not Modified",
+			headers:            map[string]string{"If-Modified-Since": modTime.Format(http.TimeFormat)},
+			expectedStatusCode: http.StatusNotModified,
+			expectedHeaders:    map[string]string{HeaderLastModified: modTime.Format(http.TimeFormat)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			helmet := Empty()
+			helmet.ContentSecurityPolicy.AddNonce(DirectiveScriptSrc)
+			helmet.Use(NewNonceInjector())
+
+			var nonce string
+			fileServer := http.FileServer(http.FS(files))
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nonce = CSPNonce(r.Context())
+				fileServer.ServeHTTP(w, r)
+			})
+
+			rr := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/page.html", nil)
+			r.TLS = &tls.ConnectionState{}
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+			helmet.Secure(handler).ServeHTTP(rr, r)
+			resp := rr.Result()
+
+			if resp.StatusCode != tc.expectedStatusCode {
+				t.Errorf("Incorrect status code\tExpected: %d\tActual: %d\n", tc.expectedStatusCode, resp.StatusCode)
+			}
+
+			expectedBody := strings.ReplaceAll(tc.expectedBody, "NONCE", nonce)
+			if body := rr.Body.String(); body != expectedBody {
+				t.Errorf("Incorrect body\tExpected: %s\tActual: %s\n", expectedBody, body)
+			}
+
+			for name, expected := range tc.expectedHeaders {
+				if header := resp.Header.Get(name); header != expected {
+					t.Errorf("Incorrect %s\tExpected: %s\tActual: %s\n", name, expected, header)
+				}
+			}
+		})
+	}
+}
+
+func TestNonceInjector_interfaces(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		w                func(rr *httptest.ResponseRecorder) http.ResponseWriter
+		expectedFlusher  bool
+		expectedHijacker bool
+	}{
+		{name: "Plain", w: func(rr *httptest.ResponseRecorder) http.ResponseWriter { return plainResponseWriter{rr} }},
+		{name: "Flusher", w: func(rr *httptest.ResponseRecorder) http.ResponseWriter { return rr }, expectedFlusher: true},
+		{
+			name: "Hijacker",
+			w: func(rr *httptest.ResponseRecorder) http.ResponseWriter {
+				return &hijackableResponseWriter{plainResponseWriter: plainResponseWriter{rr}}
+			},
+			expectedHijacker: true,
+		},
+		{
+			name: "Flusher And Hijacker",
+			w: func(rr *httptest.ResponseRecorder) http.ResponseWriter {
+				return &flushHijackableResponseWriter{ResponseRecorder: rr}
+			},
+			expectedFlusher:  true,
+			expectedHijacker: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr, r := newRecorderRequest(t)
+			r = withCSPNonce(r)
+			nw := NewNonceInjector().WrapResponseWriter(tc.w(rr), r)
+			nw.Header().Set(HeaderContentType, "text/html")
+
+			flusher, isFlusher := nw.(http.Flusher)
+			if isFlusher != tc.expectedFlusher {
+				t.Errorf("Incorrect http.Flusher\tExpected: %t\tActual: %t\n", tc.expectedFlusher, isFlusher)
+			}
+
+			hijacker, isHijacker := nw.(http.Hijacker)
+			if isHijacker != tc.expectedHijacker {
+				t.Errorf("Incorrect http.Hijacker\tExpected: %t\tActual: %t\n", tc.expectedHijacker, isHijacker)
+			}
+
+			expectedBody := `<script nonce="` + CSPNonce(r.Context()) + `"></script>`
+			if isHijacker {
+				if _, _, err := hijacker.Hijack(); err != nil {
+					t.Fatalf("Unexpected error: %v\n", err)
+				}
+				// the http.Handler owns the connection now, so nothing is rewritten
+				expectedBody = "<script></script>"
+			}
+
+			nw.Write([]byte("<script>"))
+			if isFlusher {
+				flusher.Flush()
+				if !rr.Flushed {
+					t.Errorf("Flush should flush the underlying http.ResponseWriter\n")
+				}
+			}
+			nw.Write([]byte("</script>"))
+
+			if body := rr.Body.String(); body != expectedBody {
+				t.Errorf("Incorrect body\tExpected: %s\tActual: %s\n", expectedBody, body)
+			}
+		})
+	}
+}
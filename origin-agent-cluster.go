@@ -0,0 +1,37 @@
+package helmet
+
+import "net/http"
+
+// HeaderOriginAgentCluster is the Origin-Agent-Cluster HTTP security header.
+const HeaderOriginAgentCluster = "Origin-Agent-Cluster"
+
+// Origin-Agent-Cluster options.
+const (
+	OriginAgentClusterEnabled  OriginAgentCluster = "?1"
+	OriginAgentClusterDisabled OriginAgentCluster = "?0"
+)
+
+// OriginAgentCluster represents the Origin-Agent-Cluster HTTP security header.
+type OriginAgentCluster string
+
+func (oac OriginAgentCluster) String() string {
+	return string(oac)
+}
+
+// Empty returns whether the Origin-Agent-Cluster is empty.
+func (oac OriginAgentCluster) Empty() bool {
+	return oac.String() == ""
+}
+
+// HeaderFor adds the Origin-Agent-Cluster HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (oac OriginAgentCluster) HeaderFor(w http.ResponseWriter, r *http.Request) {
+	if !oac.Empty() {
+		w.Header().Set(HeaderOriginAgentCluster, oac.String())
+	}
+}
+
+// Header adds the Origin-Agent-Cluster HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (oac OriginAgentCluster) Header(w http.ResponseWriter) {
+	oac.HeaderFor(w, nil)
+}
@@ -0,0 +1,55 @@
+package helmet
+
+import "testing"
+
+func TestOriginAgentCluster_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		originAgentCluster OriginAgentCluster
+		expectedHeader     string
+	}{
+		{name: "Empty", originAgentCluster: "", expectedHeader: ""},
+		{name: "Enabled", originAgentCluster: OriginAgentClusterEnabled, expectedHeader: "?1"},
+		{name: "Disabled", originAgentCluster: OriginAgentClusterDisabled, expectedHeader: "?0"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := tc.originAgentCluster.String()
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+}
+
+func TestOriginAgentCluster_Empty(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name               string
+		originAgentCluster OriginAgentCluster
+		expectedEmpty      bool
+	}{
+		{name: "Empty", originAgentCluster: "", expectedEmpty: true},
+		{name: "Enabled", originAgentCluster: OriginAgentClusterEnabled, expectedEmpty: false},
+		{name: "Disabled", originAgentCluster: OriginAgentClusterDisabled, expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.originAgentCluster.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
@@ -67,9 +67,15 @@ func (rp *ReferrerPolicy) Empty() bool {
 	return len(rp.directives) == 0
 }
 
-// Header adds the Referrer-Policy HTTP header to the given http.ResponseWriter.
-func (rp *ReferrerPolicy) Header(w http.ResponseWriter) {
+// HeaderFor adds the Referrer-Policy HTTP header for the given http.Request to the given http.ResponseWriter.
+func (rp *ReferrerPolicy) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !rp.Empty() {
 		w.Header().Set(HeaderReferrerPolicy, rp.String())
 	}
 }
+
+// Header adds the Referrer-Policy HTTP header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (rp *ReferrerPolicy) Header(w http.ResponseWriter) {
+	rp.HeaderFor(w, nil)
+}
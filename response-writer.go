@@ -0,0 +1,120 @@
+package helmet
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// hookedResponseWriter calls beforeWrite exactly once, right before the response headers are written,
+// or when it is closed if the http.Handler never wrote anything.
+type hookedResponseWriter struct {
+	http.ResponseWriter
+
+	beforeWrite func()
+	hooked      bool
+}
+
+// wrappedResponseWriter is an http.ResponseWriter that wraps another one, and decides itself what flushing and
+// hijacking mean for it, if the underlying http.ResponseWriter supports them.
+type wrappedResponseWriter interface {
+	http.ResponseWriter
+	io.Closer
+	Unwrap() http.ResponseWriter
+
+	flush()
+	hijack() (net.Conn, *bufio.ReadWriter, error)
+}
+
+// The variants of a wrappedResponseWriter that keep the optional interfaces of the underlying http.ResponseWriter,
+// so that e.g. streaming and WebSocket upgrades keep working, without claiming ones it doesn't implement.
+type (
+	responseFlusher       struct{ wrappedResponseWriter }
+	responseHijacker      struct{ wrappedResponseWriter }
+	responseFlushHijacker struct{ wrappedResponseWriter }
+)
+
+// withOptionalInterfaces returns the variant of w that matches which of http.Flusher and http.Hijacker
+// the http.ResponseWriter it wraps implements.
+func withOptionalInterfaces(w wrappedResponseWriter) http.ResponseWriter {
+	_, isFlusher := w.Unwrap().(http.Flusher)
+	_, isHijacker := w.Unwrap().(http.Hijacker)
+	switch {
+	case isFlusher && isHijacker:
+		return responseFlushHijacker{w}
+	case isFlusher:
+		return responseFlusher{w}
+	case isHijacker:
+		return responseHijacker{w}
+	default:
+		return w
+	}
+}
+
+// Flush flushes the wrappedResponseWriter.
+func (w responseFlusher) Flush() {
+	w.flush()
+}
+
+// Hijack hands the connection over to the http.Handler.
+func (w responseHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.hijack()
+}
+
+// Flush flushes the wrappedResponseWriter.
+func (w responseFlushHijacker) Flush() {
+	w.flush()
+}
+
+// Hijack hands the connection over to the http.Handler.
+func (w responseFlushHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.hijack()
+}
+
+// newHookedResponseWriter wraps w in a hookedResponseWriter that keeps which of http.Flusher and http.Hijacker
+// it implements.
+func newHookedResponseWriter(w http.ResponseWriter, beforeWrite func()) http.ResponseWriter {
+	return withOptionalInterfaces(&hookedResponseWriter{ResponseWriter: w, beforeWrite: beforeWrite})
+}
+
+func (hw *hookedResponseWriter) hook() {
+	if !hw.hooked {
+		hw.hooked = true
+		hw.beforeWrite()
+	}
+}
+
+// WriteHeader calls the hook before writing the response headers.
+func (hw *hookedResponseWriter) WriteHeader(statusCode int) {
+	hw.hook()
+	hw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write calls the hook before writing the response body.
+func (hw *hookedResponseWriter) Write(b []byte) (int, error) {
+	hw.hook()
+	return hw.ResponseWriter.Write(b)
+}
+
+// Close calls the hook if the http.Handler never wrote anything, since the headers are still written afterwards.
+func (hw *hookedResponseWriter) Close() error {
+	hw.hook()
+	return nil
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+func (hw *hookedResponseWriter) Unwrap() http.ResponseWriter {
+	return hw.ResponseWriter
+}
+
+// flush calls the hook before flushing, since flushing writes the response headers.
+func (hw *hookedResponseWriter) flush() {
+	hw.hook()
+	hw.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijack hands the connection over without calling the hook, since the response headers are never written.
+func (hw *hookedResponseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return hw.ResponseWriter.(http.Hijacker).Hijack()
+}
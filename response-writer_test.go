@@ -0,0 +1,165 @@
+package helmet
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHookedResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		write func(w http.ResponseWriter)
+	}{
+		{name: "WriteHeader", write: func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) }},
+		{name: "Write", write: func(w http.ResponseWriter) { w.Write([]byte("OK")) }},
+		{name: "Flush", write: func(w http.ResponseWriter) { w.(http.Flusher).Flush() }},
+		{name: "Close", write: func(w http.ResponseWriter) { w.(io.Closer).Close() }},
+		{name: "Everything", write: func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+			w.(http.Flusher).Flush()
+			w.(io.Closer).Close()
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			calls := 0
+			hw := newHookedResponseWriter(rr, func() {
+				calls++
+				rr.Header().Set("X-Hooked", "true")
+			})
+
+			tc.write(hw)
+
+			if calls != 1 {
+				t.Errorf("Hook should be called exactly once\tActual: %d\n", calls)
+			}
+
+			if header := rr.Result().Header.Get("X-Hooked"); header != "true" {
+				t.Errorf("Hook should run before the headers are written\tActual: %s\n", header)
+			}
+
+			if hw.(interface{ Unwrap() http.ResponseWriter }).Unwrap() != rr {
+				t.Errorf("Unwrap should return the underlying http.ResponseWriter\n")
+			}
+		})
+	}
+}
+
+// plainResponseWriter only implements http.ResponseWriter.
+type plainResponseWriter struct {
+	rr *httptest.ResponseRecorder
+}
+
+func (w plainResponseWriter) Header() http.Header {
+	return w.rr.Header()
+}
+
+func (w plainResponseWriter) Write(b []byte) (int, error) {
+	return w.rr.Write(b)
+}
+
+func (w plainResponseWriter) WriteHeader(statusCode int) {
+	w.rr.WriteHeader(statusCode)
+}
+
+// hijackableResponseWriter implements http.Hijacker, but not http.Flusher.
+type hijackableResponseWriter struct {
+	plainResponseWriter
+	hijacked bool
+}
+
+func (w *hijackableResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+// flushHijackableResponseWriter implements both http.Flusher and http.Hijacker.
+type flushHijackableResponseWriter struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (w *flushHijackableResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+func TestHookedResponseWriter_interfaces(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		w                func() http.ResponseWriter
+		expectedFlusher  bool
+		expectedHijacker bool
+	}{
+		{name: "Plain", w: func() http.ResponseWriter { return plainResponseWriter{httptest.NewRecorder()} }},
+		{name: "Flusher", w: func() http.ResponseWriter { return httptest.NewRecorder() }, expectedFlusher: true},
+		{
+			name: "Hijacker",
+			w: func() http.ResponseWriter {
+				return &hijackableResponseWriter{plainResponseWriter: plainResponseWriter{httptest.NewRecorder()}}
+			},
+			expectedHijacker: true,
+		},
+		{
+			name: "Flusher And Hijacker",
+			w: func() http.ResponseWriter {
+				return &flushHijackableResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+			},
+			expectedFlusher:  true,
+			expectedHijacker: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := tc.w()
+			hw := newHookedResponseWriter(w, func() {})
+
+			flusher, isFlusher := hw.(http.Flusher)
+			if isFlusher != tc.expectedFlusher {
+				t.Errorf("Incorrect http.Flusher\tExpected: %t\tActual: %t\n", tc.expectedFlusher, isFlusher)
+			}
+			if isFlusher {
+				flusher.Flush()
+			}
+
+			hijacker, isHijacker := hw.(http.Hijacker)
+			if isHijacker != tc.expectedHijacker {
+				t.Errorf("Incorrect http.Hijacker\tExpected: %t\tActual: %t\n", tc.expectedHijacker, isHijacker)
+			}
+			if isHijacker {
+				if _, _, err := hijacker.Hijack(); err != nil {
+					t.Fatalf("Unexpected error: %v\n", err)
+				}
+
+				hijacked := false
+				switch w := w.(type) {
+				case *hijackableResponseWriter:
+					hijacked = w.hijacked
+				case *flushHijackableResponseWriter:
+					hijacked = w.hijacked
+				}
+				if !hijacked {
+					t.Errorf("Hijack should hijack the underlying http.ResponseWriter\n")
+				}
+			}
+		})
+	}
+}
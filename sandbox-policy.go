@@ -0,0 +1,133 @@
+package helmet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sandboxFlags are every flag that the Content-Security-Policy sandbox directive accepts.
+var sandboxFlags = map[CSPSource]bool{
+	SandboxAllowDownloads:                       true,
+	SandboxAllowDownloadsWithoutUserActivation:  true,
+	SandboxAllowForms:                           true,
+	SandboxAllowModals:                          true,
+	SandboxAllowOrientationLock:                 true,
+	SandboxAllowPointerLock:                     true,
+	SandboxAllowPopups:                          true,
+	SandboxAllowPopupsToEscapeSandbox:           true,
+	SandboxAllowPresentation:                    true,
+	SandboxAllowSameOrigin:                      true,
+	SandboxAllowScripts:                         true,
+	SandboxAllowStorageAccessByUserActivatation: true,
+	SandboxAllowTopNavigation:                   true,
+	SandboxAllowTopNavigationByUserActivation:   true,
+	SandboxAllowTopNavigationToCustomProtocols:  true,
+}
+
+// SandboxPolicy represents the flags of the Content-Security-Policy sandbox directive.
+// Without flags, every restriction of the sandbox applies.
+type SandboxPolicy struct {
+	flags []CSPSource
+}
+
+// NewSandboxPolicy creates a new SandboxPolicy that lifts the restrictions of the given flags,
+// or returns an error if one of them isn't a sandbox flag.
+func NewSandboxPolicy(flags ...CSPSource) (*SandboxPolicy, error) {
+	sp := &SandboxPolicy{[]CSPSource{}}
+
+	for _, flag := range flags {
+		if !sandboxFlags[flag] {
+			return nil, fmt.Errorf("helmet: %q is not a sandbox flag", flag)
+		}
+
+		if !sp.Has(flag) {
+			sp.flags = append(sp.flags, flag)
+		}
+	}
+
+	return sp, nil
+}
+
+// Flags returns the flags of the SandboxPolicy.
+func (sp *SandboxPolicy) Flags() []CSPSource {
+	return append([]CSPSource{}, sp.flags...)
+}
+
+// Has returns whether the SandboxPolicy has the given flag.
+func (sp *SandboxPolicy) Has(flag CSPSource) bool {
+	for _, f := range sp.flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func (sp *SandboxPolicy) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", DirectiveSandbox, joinCSPSources(sp.flags)))
+}
+
+// Warnings returns a Warning for every combination of flags that lets the sandboxed document escape the sandbox.
+func (sp *SandboxPolicy) Warnings() []Warning {
+	return sandboxWarnings(sp.flags)
+}
+
+// Apply sets the sandbox directive of the given Content-Security-Policy, replacing whatever it was configured to before.
+func (sp *SandboxPolicy) Apply(csp *ContentSecurityPolicy) {
+	csp.Remove(DirectiveSandbox)
+	csp.Add(DirectiveSandbox, sp.flags...)
+}
+
+// sandboxWarnings flags sandbox flags that undermine the sandbox, and sources that aren't sandbox flags at all.
+func sandboxWarnings(flags []CSPSource) []Warning {
+	warnings := []Warning{}
+
+	has := map[CSPSource]bool{}
+	for _, flag := range flags {
+		has[flag] = true
+
+		if !sandboxFlags[flag] {
+			warnings = append(warnings, Warning{
+				HeaderContentSecurityPolicy,
+				fmt.Sprintf("%s is not a sandbox flag, browsers ignore it", flag),
+			})
+		}
+	}
+
+	if has[SandboxAllowScripts] && has[SandboxAllowSameOrigin] {
+		warnings = append(warnings, Warning{
+			HeaderContentSecurityPolicy,
+			fmt.Sprintf("sandbox %s with %s lets the sandboxed document's scripts remove the sandbox altogether", SandboxAllowScripts, SandboxAllowSameOrigin),
+		})
+	}
+
+	if has[SandboxAllowTopNavigation] {
+		warnings = append(warnings, Warning{
+			HeaderContentSecurityPolicy,
+			fmt.Sprintf("sandbox %s lets the sandboxed document navigate the top-level page without user activation, use %s instead", SandboxAllowTopNavigation, SandboxAllowTopNavigationByUserActivation),
+		})
+	}
+
+	return warnings
+}
+
+// sandboxWarnings flags a sandbox directive of the Content-Security-Policy that can be escaped.
+func (h *Helmet) sandboxWarnings() []Warning {
+	if h.ContentSecurityPolicy == nil || h.disabled[strings.ToLower(HeaderContentSecurityPolicy)] {
+		return nil
+	}
+
+	flags, ok := h.ContentSecurityPolicy.policies[DirectiveSandbox]
+	if !ok {
+		return nil
+	}
+	return sandboxWarnings(flags)
+}
+
+func joinCSPSources(sources []CSPSource) string {
+	strs := []string{}
+	for _, source := range sources {
+		strs = append(strs, string(source))
+	}
+	return strings.Join(strs, " ")
+}
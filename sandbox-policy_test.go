@@ -0,0 +1,121 @@
+package helmet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSandboxPolicy(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		flags          []CSPSource
+		expectedError  bool
+		expectedString string
+	}{
+		{name: "No Flags", flags: nil, expectedString: "sandbox"},
+		{name: "Flags", flags: []CSPSource{SandboxAllowForms, SandboxAllowScripts}, expectedString: "sandbox allow-forms allow-scripts"},
+		{name: "Duplicate Flags", flags: []CSPSource{SandboxAllowForms, SandboxAllowForms}, expectedString: "sandbox allow-forms"},
+		{name: "Not A Flag", flags: []CSPSource{SandboxAllowForms, SourceSelf}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sp, err := NewSandboxPolicy(tc.flags...)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error: %t\tActual: %v\n", tc.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if str := sp.String(); str != tc.expectedString {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedString, str)
+			}
+
+			csp := EmptyContentSecurityPolicy()
+			sp.Apply(csp)
+			if str := csp.String(); str != tc.expectedString {
+				t.Errorf("Incorrect Content-Security-Policy\tExpected: %s\tActual: %s\n", tc.expectedString, str)
+			}
+		})
+	}
+}
+
+func TestSandboxPolicy_Warnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		flags            []CSPSource
+		expectedWarnings []string // substrings, one per Warning, in order
+	}{
+		{name: "No Flags", flags: nil, expectedWarnings: []string{}},
+		{name: "Scripts", flags: []CSPSource{SandboxAllowScripts}, expectedWarnings: []string{}},
+		{
+			name:             "Scripts, Same Origin",
+			flags:            []CSPSource{SandboxAllowSameOrigin, SandboxAllowScripts},
+			expectedWarnings: []string{"remove the sandbox"},
+		},
+		{
+			name:             "Top Navigation",
+			flags:            []CSPSource{SandboxAllowTopNavigation},
+			expectedWarnings: []string{"without user activation"},
+		},
+		{name: "Top Navigation By User Activation", flags: []CSPSource{SandboxAllowTopNavigationByUserActivation}, expectedWarnings: []string{}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sp, err := NewSandboxPolicy(tc.flags...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+
+			warnings := sp.Warnings()
+			if len(warnings) != len(tc.expectedWarnings) {
+				t.Fatalf("Incorrect warnings\tExpected: %v\tActual: %v\n", tc.expectedWarnings, warnings)
+			}
+
+			for i, warning := range warnings {
+				if !strings.Contains(warning.String(), tc.expectedWarnings[i]) {
+					t.Errorf("Incorrect warning\tExpected: %s\tActual: %s\n", tc.expectedWarnings[i], warning)
+				}
+			}
+		})
+	}
+}
+
+func TestHelmet_sandboxWarnings(t *testing.T) {
+	t.Parallel()
+
+	helmet := Default()
+	helmet.ContentSecurityPolicy.Add(DirectiveSandbox, SandboxAllowScripts, SourceSelf, SandboxAllowSameOrigin)
+
+	warnings := helmet.sandboxWarnings()
+	messages := []string{}
+	for _, warning := range warnings {
+		messages = append(messages, warning.Message)
+	}
+
+	expected := []string{
+		"'self' is not a sandbox flag, browsers ignore it",
+		"sandbox allow-scripts with allow-same-origin lets the sandboxed document's scripts remove the sandbox altogether",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %v\tActual: %v\n", expected, messages)
+	}
+
+	helmet.Disable(HeaderContentSecurityPolicy)
+	if warnings := helmet.sandboxWarnings(); len(warnings) != 0 {
+		t.Errorf("A disabled Content-Security-Policy should not warn\tActual: %v\n", warnings)
+	}
+}
@@ -0,0 +1,252 @@
+package helmet
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// HeaderSetCookie is the Set-Cookie HTTP header.
+const HeaderSetCookie = "Set-Cookie"
+
+// List of all cookie name prefixes that browsers enforce extra rules for.
+const (
+	CookiePrefixHost   = "__Host-"
+	CookiePrefixSecure = "__Secure-"
+)
+
+// List of all cookie SameSite values.
+const (
+	CookieSameSiteStrict CookieSameSite = "Strict"
+	CookieSameSiteLax    CookieSameSite = "Lax"
+	CookieSameSiteNone   CookieSameSite = "None"
+)
+
+type (
+	// CookieSameSite represents the SameSite attribute of a cookie.
+	CookieSameSite string
+
+	// CookiePolicy represents the attributes that every matching cookie must have.
+	CookiePolicy struct {
+		Secure      bool
+		HTTPOnly    bool
+		SameSite    CookieSameSite // only added to cookies that don't specify their own SameSite
+		Partitioned bool
+	}
+
+	// SecureCookies hardens the cookies set by the next http.Handler by rewriting its Set-Cookie HTTP headers.
+	// Regardless of policy, cookies named with the __Secure- and __Host- prefixes are fixed up to meet the prefix rules.
+	SecureCookies struct {
+		// The CookiePolicy applied to every cookie without an override.
+		Default CookiePolicy
+
+		// CookiePolicies applied to specific cookies, by cookie name, instead of the default.
+		Overrides map[string]CookiePolicy
+
+		// Whether to only log violations, instead of rewriting the cookies.
+		ReportOnly bool
+
+		// Where violations are logged in report-only mode. Defaults to the standard logger.
+		Logger *log.Logger
+	}
+)
+
+// NewSecureCookies creates a new SecureCookies.
+func NewSecureCookies(policy CookiePolicy) *SecureCookies {
+	return &SecureCookies{
+		Default:   policy,
+		Overrides: map[string]CookiePolicy{},
+	}
+}
+
+// EmptySecureCookies creates a blank slate SecureCookies.
+func EmptySecureCookies() *SecureCookies {
+	return NewSecureCookies(CookiePolicy{})
+}
+
+// Name returns the Set-Cookie HTTP header.
+func (sc *SecureCookies) Name() string {
+	return HeaderSetCookie
+}
+
+// Empty returns whether the SecureCookies is empty.
+func (sc *SecureCookies) Empty() bool {
+	return sc.Default == CookiePolicy{} && len(sc.Overrides) == 0
+}
+
+// Header does nothing, since cookies can only be hardened once the next http.Handler has set them.
+func (sc *SecureCookies) Header(w http.ResponseWriter, r *http.Request) {}
+
+// WrapResponseWriter returns an http.ResponseWriter that hardens the Set-Cookie HTTP headers right before they are written.
+func (sc *SecureCookies) WrapResponseWriter(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	return newHookedResponseWriter(w, func() {
+		sc.harden(w.Header())
+	})
+}
+
+func (sc *SecureCookies) harden(header http.Header) {
+	setCookies := header.Values(HeaderSetCookie)
+	if len(setCookies) == 0 {
+		return
+	}
+
+	hardened := make([]string, 0, len(setCookies))
+	for _, setCookie := range setCookies {
+		cookie := parseSetCookie(setCookie)
+
+		violations := sc.enforce(cookie)
+		if sc.ReportOnly {
+			sc.report(cookie.name(), violations)
+			hardened = append(hardened, setCookie)
+		} else {
+			hardened = append(hardened, cookie.String())
+		}
+	}
+	header[HeaderSetCookie] = hardened
+}
+
+// enforce rewrites the cookie to follow its CookiePolicy and the prefix rules, returning every violation it fixed.
+func (sc *SecureCookies) enforce(cookie *setCookie) []string {
+	policy, ok := sc.Overrides[cookie.name()]
+	if !ok {
+		policy = sc.Default
+	}
+
+	violations := []string{}
+	require := func(attribute, value, reason string) {
+		if !cookie.has(attribute) {
+			cookie.set(attribute, value)
+			violations = append(violations, reason)
+		}
+	}
+
+	if policy.HTTPOnly {
+		require("HttpOnly", "", "missing HttpOnly")
+	}
+	if policy.SameSite != "" {
+		require("SameSite", string(policy.SameSite), fmt.Sprintf("missing SameSite, defaulting to %s", policy.SameSite))
+	}
+	if policy.Partitioned {
+		require("Partitioned", "", "missing Partitioned")
+	}
+
+	switch {
+	case policy.Secure:
+		require("Secure", "", "missing Secure")
+	case strings.EqualFold(cookie.get("SameSite"), string(CookieSameSiteNone)):
+		require("Secure", "", "SameSite=None requires Secure")
+	case cookie.has("Partitioned"):
+		require("Secure", "", "Partitioned requires Secure")
+	}
+
+	if strings.HasPrefix(cookie.name(), CookiePrefixSecure) {
+		require("Secure", "", "__Secure- prefix requires Secure")
+	}
+
+	if strings.HasPrefix(cookie.name(), CookiePrefixHost) {
+		require("Secure", "", "__Host- prefix requires Secure")
+
+		if cookie.has("Domain") {
+			cookie.remove("Domain")
+			violations = append(violations, "__Host- prefix forbids Domain")
+		}
+
+		if cookie.get("Path") != "/" {
+			cookie.remove("Path")
+			cookie.set("Path", "/")
+			violations = append(violations, "__Host- prefix requires Path=/")
+		}
+	}
+
+	return violations
+}
+
+func (sc *SecureCookies) report(name string, violations []string) {
+	logger := sc.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	for _, violation := range violations {
+		logger.Printf("helmet: cookie %q: %s", name, violation)
+	}
+}
+
+type (
+	// setCookie is a Set-Cookie HTTP header that can be rewritten without losing attributes unknown to net/http.
+	setCookie struct {
+		nameValue  string
+		attributes []cookieAttribute
+	}
+
+	cookieAttribute struct {
+		key   string
+		value string
+	}
+)
+
+func parseSetCookie(header string) *setCookie {
+	parts := strings.Split(header, ";")
+
+	cookie := &setCookie{strings.TrimSpace(parts[0]), []cookieAttribute{}}
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(part, "=")
+		cookie.attributes = append(cookie.attributes, cookieAttribute{strings.TrimSpace(key), strings.TrimSpace(value)})
+	}
+	return cookie
+}
+
+func (c *setCookie) name() string {
+	name, _, _ := strings.Cut(c.nameValue, "=")
+	return strings.TrimSpace(name)
+}
+
+func (c *setCookie) has(key string) bool {
+	for _, attribute := range c.attributes {
+		if strings.EqualFold(attribute.key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *setCookie) get(key string) string {
+	for _, attribute := range c.attributes {
+		if strings.EqualFold(attribute.key, key) {
+			return attribute.value
+		}
+	}
+	return ""
+}
+
+func (c *setCookie) set(key string, value string) {
+	c.attributes = append(c.attributes, cookieAttribute{key, value})
+}
+
+func (c *setCookie) remove(key string) {
+	attributes := []cookieAttribute{}
+	for _, attribute := range c.attributes {
+		if !strings.EqualFold(attribute.key, key) {
+			attributes = append(attributes, attribute)
+		}
+	}
+	c.attributes = attributes
+}
+
+func (c *setCookie) String() string {
+	builder := []string{c.nameValue}
+	for _, attribute := range c.attributes {
+		if attribute.value == "" {
+			builder = append(builder, attribute.key)
+		} else {
+			builder = append(builder, fmt.Sprintf("%s=%s", attribute.key, attribute.value))
+		}
+	}
+	return strings.Join(builder, "; ")
+}
@@ -0,0 +1,187 @@
+package helmet
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSecureCookies_Empty(t *testing.T) {
+	t.Parallel()
+
+	withOverride := EmptySecureCookies()
+	withOverride.Overrides["session"] = CookiePolicy{HTTPOnly: true}
+
+	testCases := []struct {
+		name          string
+		secureCookies *SecureCookies
+		expectedEmpty bool
+	}{
+		{name: "Empty", secureCookies: EmptySecureCookies(), expectedEmpty: true},
+		{name: "Default Policy", secureCookies: NewSecureCookies(CookiePolicy{Secure: true}), expectedEmpty: false},
+		{name: "Override", secureCookies: withOverride, expectedEmpty: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exists := tc.secureCookies.Empty()
+			if exists != tc.expectedEmpty {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedEmpty, exists)
+			}
+		})
+	}
+}
+
+func TestSecureCookies_harden(t *testing.T) {
+	t.Parallel()
+
+	strict := CookiePolicy{Secure: true, HTTPOnly: true, SameSite: CookieSameSiteLax}
+
+	testCases := []struct {
+		name           string
+		policy         CookiePolicy
+		overrides      map[string]CookiePolicy
+		setCookie      string
+		expectedCookie string
+	}{
+		{
+			name:           "Bare Cookie",
+			policy:         strict,
+			setCookie:      "id=1",
+			expectedCookie: "id=1; HttpOnly; SameSite=Lax; Secure",
+		},
+		{
+			name:           "Already Hardened",
+			policy:         strict,
+			setCookie:      "id=1; Path=/; Secure; HttpOnly; SameSite=Strict",
+			expectedCookie: "id=1; Path=/; Secure; HttpOnly; SameSite=Strict",
+		},
+		{
+			name:           "Unknown Attributes Preserved",
+			policy:         strict,
+			setCookie:      "id=1; Priority=High; Max-Age=60",
+			expectedCookie: "id=1; Priority=High; Max-Age=60; HttpOnly; SameSite=Lax; Secure",
+		},
+		{
+			name:           "Partitioned",
+			policy:         CookiePolicy{Partitioned: true},
+			setCookie:      "id=1",
+			expectedCookie: "id=1; Partitioned; Secure",
+		},
+		{
+			name:           "SameSite None Requires Secure",
+			policy:         CookiePolicy{HTTPOnly: true},
+			setCookie:      "id=1; SameSite=None",
+			expectedCookie: "id=1; SameSite=None; HttpOnly; Secure",
+		},
+		{
+			name:           "Secure Prefix",
+			policy:         CookiePolicy{},
+			setCookie:      "__Secure-id=1",
+			expectedCookie: "__Secure-id=1; Secure",
+		},
+		{
+			name:           "Host Prefix",
+			policy:         CookiePolicy{},
+			setCookie:      "__Host-id=1; Domain=example.com; Path=/account",
+			expectedCookie: "__Host-id=1; Secure; Path=/",
+		},
+		{
+			name:           "Override",
+			policy:         strict,
+			overrides:      map[string]CookiePolicy{"csrf": {Secure: true, SameSite: CookieSameSiteStrict}},
+			setCookie:      "csrf=abc",
+			expectedCookie: "csrf=abc; SameSite=Strict; Secure",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sc := NewSecureCookies(tc.policy)
+			if tc.overrides != nil {
+				sc.Overrides = tc.overrides
+			}
+
+			header := http.Header{}
+			header.Add(HeaderSetCookie, tc.setCookie)
+			sc.harden(header)
+
+			if cookie := header.Get(HeaderSetCookie); cookie != tc.expectedCookie {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedCookie, cookie)
+			}
+		})
+	}
+}
+
+func TestSecureCookies_reportOnly(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	sc := NewSecureCookies(CookiePolicy{Secure: true, HTTPOnly: true})
+	sc.ReportOnly = true
+	sc.Logger = log.New(&logs, "", 0)
+
+	header := http.Header{}
+	header.Add(HeaderSetCookie, "id=1")
+	header.Add(HeaderSetCookie, "ok=1; Secure; HttpOnly")
+	sc.harden(header)
+
+	cookies := header.Values(HeaderSetCookie)
+	if len(cookies) != 2 || cookies[0] != "id=1" || cookies[1] != "ok=1; Secure; HttpOnly" {
+		t.Errorf("Cookies should not be rewritten in report-only mode\tActual: %v\n", cookies)
+	}
+
+	expectedLogs := "helmet: cookie \"id\": missing HttpOnly\nhelmet: cookie \"id\": missing Secure\n"
+	if logs.String() != expectedLogs {
+		t.Errorf("Expected: %q\tActual: %q\n", expectedLogs, logs.String())
+	}
+}
+
+func TestHelmet_Secure_secureCookies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "Write",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.SetCookie(w, &http.Cookie{Name: "id", Value: "1"})
+				w.Write([]byte("OK"))
+			},
+		},
+		{
+			name: "No Write",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.SetCookie(w, &http.Cookie{Name: "id", Value: "1"})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr, r := newRecorderRequest(t)
+
+			helmet := Empty()
+			helmet.Use(NewSecureCookies(CookiePolicy{Secure: true, HTTPOnly: true}))
+			helmet.Secure(tc.handler).ServeHTTP(rr, r)
+
+			cookie := rr.Result().Header.Get(HeaderSetCookie)
+			if !strings.Contains(cookie, "Secure") || !strings.Contains(cookie, "HttpOnly") {
+				t.Errorf("Cookie should be hardened\tActual: %s\n", cookie)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 )
 
 // HeaderStrictTransportSecurity is the Strict-Transport-Security HTTP security header.
@@ -38,7 +39,15 @@ type (
 		// After successfully submitting your domain to Google maintained HSTS preload service, browsers will never connect to your domain using an insecure connection.
 		Preload bool
 
-		cache string
+		// Proxies whose X-Forwarded-Proto and Forwarded HTTP headers are trusted to signal an HTTPS request.
+		TrustedProxies *TrustedProxies
+
+		// Optionally ramps up the max-age over time, overriding MaxAge. Preload is sent if either it or the current stage asks for it.
+		Schedule *HSTSSchedule
+
+		mu         sync.Mutex // guards the cache, which concurrent requests share
+		cache      string
+		cacheStage int
 	}
 )
 
@@ -56,26 +65,52 @@ func EmptyStrictTransportSecurity() *StrictTransportSecurity {
 	return NewStrictTransportSecurity(0, false, false)
 }
 
+// stage returns the index of the current HSTSStage, or 0 without a schedule.
+func (hsts *StrictTransportSecurity) stage() int {
+	if hsts.Schedule == nil || len(hsts.Schedule.Stages) == 0 {
+		return 0
+	}
+	return hsts.Schedule.current()
+}
+
+// effective returns the max-age and preload that are in effect right now, taking the schedule into account.
+func (hsts *StrictTransportSecurity) effective() (int, bool) {
+	if hsts.Schedule == nil || len(hsts.Schedule.Stages) == 0 {
+		return hsts.MaxAge, hsts.Preload
+	}
+
+	stage := hsts.Schedule.Stages[hsts.stage()]
+	return stage.MaxAge, hsts.Preload || stage.Preload
+}
+
 func (hsts *StrictTransportSecurity) String() string {
-	if len(hsts.cache) != 0 {
+	hsts.mu.Lock()
+	defer hsts.mu.Unlock()
+
+	// the cache is only valid for the stage it was generated in
+	stage := hsts.stage()
+	if len(hsts.cache) != 0 && hsts.cacheStage == stage {
 		return hsts.cache
 	}
+	hsts.cacheStage = stage
+
+	maxAge, preload := hsts.effective()
 
 	// max age is not optional
-	if hsts.MaxAge <= 0 {
+	if maxAge <= 0 {
 		hsts.cache = ""
 		return hsts.cache
 	}
 
 	builder := []string{
-		string(HSTSDirectiveMaxAge(hsts.MaxAge)),
+		string(HSTSDirectiveMaxAge(maxAge)),
 	}
 
 	if hsts.IncludeSubDomains {
 		builder = append(builder, string(DirectiveIncludeSubDomains))
 	}
 
-	if hsts.Preload {
+	if preload {
 		builder = append(builder, string(DirectivePreload))
 	}
 
@@ -86,12 +121,24 @@ func (hsts *StrictTransportSecurity) String() string {
 // Empty returns whether the Strict-Transport-Security is empty.
 func (hsts *StrictTransportSecurity) Empty() bool {
 	// includeSubDomains and preload are optional
-	return hsts.MaxAge == 0
+	maxAge, _ := hsts.effective()
+	return maxAge == 0
 }
 
-// Header adds the Strict-Transport-Security HTTP security header to the given http.ResponseWriter.
-func (hsts *StrictTransportSecurity) Header(w http.ResponseWriter) {
+// HeaderFor adds the Strict-Transport-Security HTTP security header for the given http.Request to the given http.ResponseWriter.
+// Browsers ignore it over plain HTTP, so it is only added when the http.Request was made over HTTPS.
+func (hsts *StrictTransportSecurity) HeaderFor(w http.ResponseWriter, r *http.Request) {
+	if r != nil && !hsts.TrustedProxies.IsHTTPS(r) {
+		return
+	}
+
 	if !hsts.Empty() {
 		w.Header().Set(HeaderStrictTransportSecurity, hsts.String())
 	}
 }
+
+// Header adds the Strict-Transport-Security HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (hsts *StrictTransportSecurity) Header(w http.ResponseWriter) {
+	hsts.HeaderFor(w, nil)
+}
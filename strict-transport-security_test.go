@@ -1,6 +1,10 @@
 package helmet
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 func TestStrictTransportSecurity_DirectiveMaxAge(t *testing.T) {
 	t.Parallel()
@@ -161,3 +165,126 @@ func TestStrictTransportSecurity_Empty(t *testing.T) {
 		})
 	}
 }
+
+func TestStrictTransportSecurity_HeaderFor(t *testing.T) {
+	t.Parallel()
+
+	trustedProxies, err := NewTrustedProxies("10.0.0.0/8", "2001:db8::/32")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name           string
+		url            string
+		remoteAddr     string
+		headers        map[string]string
+		expectedHeader string
+	}{
+		{name: "Direct TLS", url: "https://example.com/", remoteAddr: "203.0.113.1:1234", expectedHeader: "max-age=63072000"},
+		{name: "Direct Plain HTTP", url: "http://example.com/", remoteAddr: "203.0.113.1:1234", expectedHeader: ""},
+		{
+			name:           "Trusted Proxy, X-Forwarded-Proto HTTPS",
+			url:            "http://example.com/",
+			remoteAddr:     "10.1.2.3:1234",
+			headers:        map[string]string{HeaderXForwardedProto: "https"},
+			expectedHeader: "max-age=63072000",
+		},
+		{
+			name:           "Trusted Proxy, X-Forwarded-Proto HTTP",
+			url:            "http://example.com/",
+			remoteAddr:     "10.1.2.3:1234",
+			headers:        map[string]string{HeaderXForwardedProto: "http"},
+			expectedHeader: "",
+		},
+		{
+			name:           "Trusted Proxy, X-Forwarded-Proto Chain",
+			url:            "http://example.com/",
+			remoteAddr:     "10.1.2.3:1234",
+			headers:        map[string]string{HeaderXForwardedProto: "http, https"},
+			expectedHeader: "max-age=63072000",
+		},
+		{
+			name:           "Trusted Proxy, Forwarded HTTPS",
+			url:            "http://example.com/",
+			remoteAddr:     "10.1.2.3:1234",
+			headers:        map[string]string{HeaderForwarded: `for=192.0.2.60;proto="https";by=203.0.113.43`},
+			expectedHeader: "max-age=63072000",
+		},
+		{
+			name:           "Trusted Proxy, Forwarded Overrides X-Forwarded-Proto",
+			url:            "http://example.com/",
+			remoteAddr:     "10.1.2.3:1234",
+			headers:        map[string]string{HeaderForwarded: "proto=http", HeaderXForwardedProto: "https"},
+			expectedHeader: "",
+		},
+		{
+			name:           "Trusted IPv6 Proxy, X-Forwarded-Proto HTTPS",
+			url:            "http://example.com/",
+			remoteAddr:     "[2001:db8::1]:1234",
+			headers:        map[string]string{HeaderXForwardedProto: "https"},
+			expectedHeader: "max-age=63072000",
+		},
+		{
+			name:           "Trusted Proxy, No Forwarding Headers",
+			url:            "http://example.com/",
+			remoteAddr:     "10.1.2.3:1234",
+			expectedHeader: "",
+		},
+		{
+			name:           "Untrusted Peer, Spoofed X-Forwarded-Proto",
+			url:            "http://example.com/",
+			remoteAddr:     "203.0.113.1:1234",
+			headers:        map[string]string{HeaderXForwardedProto: "https"},
+			expectedHeader: "",
+		},
+		{
+			name:           "Untrusted Peer, Spoofed Forwarded",
+			url:            "http://example.com/",
+			remoteAddr:     "203.0.113.1:1234",
+			headers:        map[string]string{HeaderForwarded: "proto=https"},
+			expectedHeader: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hsts := NewStrictTransportSecurity(63072000, false, false)
+			hsts.TrustedProxies = trustedProxies
+
+			r := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			r.RemoteAddr = tc.remoteAddr
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+
+			rr := httptest.NewRecorder()
+			hsts.HeaderFor(rr, r)
+
+			header := rr.Result().Header.Get(HeaderStrictTransportSecurity)
+			if header != tc.expectedHeader {
+				t.Errorf("Expected: %s\tActual: %s\n", tc.expectedHeader, header)
+			}
+		})
+	}
+
+	t.Run("No Trusted Proxies", func(t *testing.T) {
+		t.Parallel()
+
+		hsts := NewStrictTransportSecurity(63072000, false, false)
+
+		r := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+		r.RemoteAddr = "10.1.2.3:1234"
+		r.Header.Set(HeaderXForwardedProto, "https")
+
+		rr := httptest.NewRecorder()
+		hsts.HeaderFor(rr, r)
+
+		if header := rr.Result().Header.Get(HeaderStrictTransportSecurity); header != "" {
+			t.Errorf("Header exists when it shouldn't: %s\n", header)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package helmet
 
 import (
+	"crypto/tls"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -19,12 +20,14 @@ func addXPoweredByHelmetMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// newRecorderRequest creates an HTTPS request, since some headers are only sent over HTTPS.
 func newRecorderRequest(t *testing.T) (*httptest.ResponseRecorder, *http.Request) {
 	rr := httptest.NewRecorder()
 	r, err := http.NewRequest(http.MethodGet, "/", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
+	r.TLS = &tls.ConnectionState{}
 	return rr, r
 }
 
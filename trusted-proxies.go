@@ -0,0 +1,104 @@
+package helmet
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// List of all HTTP headers that reverse proxies use to forward the original request's scheme.
+const (
+	HeaderForwarded       = "Forwarded"
+	HeaderXForwardedProto = "X-Forwarded-Proto"
+)
+
+// TrustedProxies represents the reverse proxies whose forwarding headers can be trusted.
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// NewTrustedProxies creates a new TrustedProxies from a list of CIDRs or single IP addresses.
+func NewTrustedProxies(cidrs ...string) (*TrustedProxies, error) {
+	tp := &TrustedProxies{[]netip.Prefix{}}
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(cidr)
+			if addrErr != nil {
+				return nil, fmt.Errorf("helmet: invalid trusted proxy %q: %w", cidr, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+
+		tp.prefixes = append(tp.prefixes, prefix.Masked())
+	}
+	return tp, nil
+}
+
+// Trusted returns whether the http.Request was sent directly by a trusted proxy.
+func (tp *TrustedProxies) Trusted(r *http.Request) bool {
+	if tp == nil || len(tp.prefixes) == 0 {
+		return false
+	}
+
+	addr, ok := remoteAddr(r)
+	if !ok {
+		return false
+	}
+
+	for _, prefix := range tp.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHTTPS returns whether the http.Request was made over HTTPS, either directly or through a trusted proxy.
+// Forwarding headers from untrusted peers are ignored.
+func (tp *TrustedProxies) IsHTTPS(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+
+	if !tp.Trusted(r) {
+		return false
+	}
+
+	return strings.EqualFold(forwardedProto(r), "https")
+}
+
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+
+	if addr, err := netip.ParseAddr(r.RemoteAddr); err == nil {
+		return addr.Unmap(), true
+	}
+
+	return netip.Addr{}, false
+}
+
+// forwardedProto returns the scheme forwarded by the closest proxy, which is the last one in the list.
+// The standard Forwarded HTTP header takes precedence over X-Forwarded-Proto.
+func forwardedProto(r *http.Request) string {
+	if forwarded := r.Header.Values(HeaderForwarded); len(forwarded) > 0 {
+		elements := strings.Split(forwarded[len(forwarded)-1], ",")
+		for _, pair := range strings.Split(elements[len(elements)-1], ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(key, "proto") {
+				return strings.Trim(value, `"`)
+			}
+		}
+		return ""
+	}
+
+	if protos := r.Header.Values(HeaderXForwardedProto); len(protos) > 0 {
+		elements := strings.Split(protos[len(protos)-1], ",")
+		return strings.TrimSpace(elements[len(elements)-1])
+	}
+
+	return ""
+}
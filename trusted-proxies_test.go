@@ -0,0 +1,131 @@
+package helmet
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedProxies_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		cidrs            []string
+		expectedPrefixes int
+		expectedErr      bool
+	}{
+		{name: "Empty", cidrs: []string{}, expectedPrefixes: 0},
+		{name: "IPv4 CIDR", cidrs: []string{"10.0.0.0/8"}, expectedPrefixes: 1},
+		{name: "IPv6 CIDR", cidrs: []string{"2001:db8::/32"}, expectedPrefixes: 1},
+		{name: "Single IP Addresses", cidrs: []string{"10.0.0.1", "::1"}, expectedPrefixes: 2},
+		{name: "Invalid", cidrs: []string{"10.0.0.0/8", "proxy.internal"}, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tp, err := NewTrustedProxies(tc.cidrs...)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("Unexpected error\tExpected: %t\tActual: %v\n", tc.expectedErr, err)
+			}
+			if tc.expectedErr {
+				return
+			}
+
+			if len(tp.prefixes) != tc.expectedPrefixes {
+				t.Errorf("Incorrect amount of prefixes\tExpected: %d\tActual: %d\n", tc.expectedPrefixes, len(tp.prefixes))
+			}
+		})
+	}
+}
+
+func TestTrustedProxies_Trusted(t *testing.T) {
+	t.Parallel()
+
+	tp, err := NewTrustedProxies("10.0.0.0/8", "192.0.2.1", "2001:db8::/32")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name            string
+		trustedProxies  *TrustedProxies
+		remoteAddr      string
+		expectedTrusted bool
+	}{
+		{name: "Nil", trustedProxies: nil, remoteAddr: "10.0.0.1:1234", expectedTrusted: false},
+		{name: "IPv4 In CIDR", trustedProxies: tp, remoteAddr: "10.255.0.1:1234", expectedTrusted: true},
+		{name: "IPv4 Single Address", trustedProxies: tp, remoteAddr: "192.0.2.1:1234", expectedTrusted: true},
+		{name: "IPv4 Outside", trustedProxies: tp, remoteAddr: "192.0.2.2:1234", expectedTrusted: false},
+		{name: "IPv4-Mapped IPv6", trustedProxies: tp, remoteAddr: "[::ffff:10.0.0.1]:1234", expectedTrusted: true},
+		{name: "IPv6 In CIDR", trustedProxies: tp, remoteAddr: "[2001:db8::1]:1234", expectedTrusted: true},
+		{name: "IPv6 Outside", trustedProxies: tp, remoteAddr: "[2001:db9::1]:1234", expectedTrusted: false},
+		{name: "No Port", trustedProxies: tp, remoteAddr: "10.0.0.1", expectedTrusted: true},
+		{name: "Malformed", trustedProxies: tp, remoteAddr: "not-an-address", expectedTrusted: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+
+			trusted := tc.trustedProxies.Trusted(r)
+			if trusted != tc.expectedTrusted {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedTrusted, trusted)
+			}
+		})
+	}
+}
+
+func TestTrustedProxies_IsHTTPS(t *testing.T) {
+	t.Parallel()
+
+	tp, err := NewTrustedProxies("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		tls           bool
+		remoteAddr    string
+		headers       map[string]string
+		expectedHTTPS bool
+	}{
+		{name: "Direct TLS", tls: true, remoteAddr: "203.0.113.1:1234", expectedHTTPS: true},
+		{name: "Direct Plain HTTP", remoteAddr: "203.0.113.1:1234", expectedHTTPS: false},
+		{name: "Trusted X-Forwarded-Proto", remoteAddr: "10.0.0.1:1234", headers: map[string]string{HeaderXForwardedProto: "HTTPS"}, expectedHTTPS: true},
+		{name: "Trusted Forwarded", remoteAddr: "10.0.0.1:1234", headers: map[string]string{HeaderForwarded: "for=192.0.2.1, for=10.0.0.2;proto=https"}, expectedHTTPS: true},
+		{name: "Trusted Forwarded Without Proto", remoteAddr: "10.0.0.1:1234", headers: map[string]string{HeaderForwarded: "for=192.0.2.1"}, expectedHTTPS: false},
+		{name: "Spoofed X-Forwarded-Proto", remoteAddr: "203.0.113.1:1234", headers: map[string]string{HeaderXForwardedProto: "https"}, expectedHTTPS: false},
+		{name: "Spoofed Forwarded", remoteAddr: "203.0.113.1:1234", headers: map[string]string{HeaderForwarded: "proto=https"}, expectedHTTPS: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+			if tc.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+
+			https := tp.IsHTTPS(r)
+			if https != tc.expectedHTTPS {
+				t.Errorf("Expected: %t\tActual: %t\n", tc.expectedHTTPS, https)
+			}
+		})
+	}
+}
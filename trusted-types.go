@@ -0,0 +1,127 @@
+package helmet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TrustedTypes represents the Content-Security-Policy trusted-types and require-trusted-types-for directives.
+//
+// Enforcing Trusted Types breaks every DOM XSS sink that is still passed a plain string, so roll it out with
+// SetTrustedTypes in report-only mode first, and enforce it once no more violations are reported.
+type TrustedTypes struct {
+	Policies         []string // names of the Trusted Types policies that may be created, none if empty
+	AllowAnyPolicy   bool     // allows creating policies with any name, the '*' wildcard
+	AllowDuplicates  bool     // allows creating several policies with the same name
+	RequireForScript bool     // requires Trusted Types for script sinks, require-trusted-types-for 'script'
+}
+
+// NewTrustedTypes creates a new TrustedTypes that requires Trusted Types for scripts, created by the given policies.
+func NewTrustedTypes(policies ...string) *TrustedTypes {
+	return &TrustedTypes{Policies: policies, RequireForScript: true}
+}
+
+// Validate returns an error if a policy name doesn't follow the Trusted Types grammar.
+func (tt *TrustedTypes) Validate() error {
+	for _, policy := range tt.Policies {
+		if !validTrustedTypesPolicyName(policy) {
+			return fmt.Errorf("helmet: invalid Trusted Types policy name %q", policy)
+		}
+	}
+	return nil
+}
+
+// validTrustedTypesPolicyName returns whether the name only contains the characters tt-policy-name allows:
+// ALPHA / DIGIT / "-" / "#" / "=" / "_" / "/" / "@" / "." / "%".
+func validTrustedTypesPolicyName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.ContainsRune("-#=_/@.%", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Sources returns the sources of the trusted-types directive: 'none' if no policy may be created.
+func (tt *TrustedTypes) Sources() []CSPSource {
+	sources := []CSPSource{}
+	for _, policy := range tt.Policies {
+		sources = append(sources, CSPSource(policy))
+	}
+
+	if tt.AllowAnyPolicy {
+		sources = append(sources, SourceWildcard)
+	}
+
+	if len(sources) == 0 {
+		// 'none' must stand alone, and there is nothing to duplicate anyway
+		return []CSPSource{SourceNone}
+	}
+
+	if tt.AllowDuplicates {
+		sources = append(sources, TrustedTypesAllowDuplicates)
+	}
+	return sources
+}
+
+// Apply sets the trusted-types and require-trusted-types-for directives of the given Content-Security-Policy,
+// replacing whatever they were configured to before.
+func (tt *TrustedTypes) Apply(csp *ContentSecurityPolicy) {
+	removeTrustedTypes(csp)
+
+	csp.Add(DirectiveTrustedTypes, tt.Sources()...)
+	if tt.RequireForScript {
+		csp.Add(DirectiveRequireTrustedTypesFor, RequireTrustedTypesForScript)
+	}
+}
+
+func removeTrustedTypes(csp *ContentSecurityPolicy) {
+	csp.Remove(DirectiveTrustedTypes, DirectiveRequireTrustedTypesFor)
+}
+
+// SetTrustedTypes validates the given TrustedTypes, and applies it to either the Content-Security-Policy
+// or, in report-only mode, the Content-Security-Policy-Report-Only, removing it from the other one.
+// The Content-Security-Policy-Report-Only inherits the reporting directives of the Content-Security-Policy
+// if it has none of its own, so that violations are reported somewhere.
+func (h *Helmet) SetTrustedTypes(tt *TrustedTypes, reportOnly bool) error {
+	if err := tt.Validate(); err != nil {
+		return err
+	}
+
+	if h.ContentSecurityPolicy == nil {
+		h.ContentSecurityPolicy = EmptyContentSecurityPolicy()
+	}
+	if h.ContentSecurityPolicyReportOnly == nil {
+		h.ContentSecurityPolicyReportOnly = EmptyContentSecurityPolicy()
+	}
+
+	if !reportOnly {
+		removeTrustedTypes(h.ContentSecurityPolicyReportOnly)
+		tt.Apply(h.ContentSecurityPolicy)
+		return nil
+	}
+
+	removeTrustedTypes(h.ContentSecurityPolicy)
+	tt.Apply(h.ContentSecurityPolicyReportOnly)
+
+	reporting := []CSPDirective{DirectiveReportTo, DeprecatedDirectiveReportURI}
+	for _, directive := range reporting {
+		if _, ok := h.ContentSecurityPolicyReportOnly.policies[directive]; ok {
+			return nil
+		}
+	}
+	for _, directive := range reporting {
+		if sources, ok := h.ContentSecurityPolicy.policies[directive]; ok {
+			h.ContentSecurityPolicyReportOnly.Add(directive, sources...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package helmet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrustedTypes_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		policies      []string
+		expectedValid bool
+	}{
+		{name: "None", policies: nil, expectedValid: true},
+		{name: "Valid", policies: []string{"default", "my-policy_1", "a#b=c/d@e.f%20"}, expectedValid: true},
+		{name: "Empty Name", policies: []string{""}, expectedValid: false},
+		{name: "Space", policies: []string{"my policy"}, expectedValid: false},
+		{name: "Quote", policies: []string{"'none'"}, expectedValid: false},
+		{name: "Wildcard", policies: []string{"*"}, expectedValid: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewTrustedTypes(tc.policies...).Validate()
+			if (err == nil) != tc.expectedValid {
+				t.Errorf("Expected valid: %t\tActual error: %v\n", tc.expectedValid, err)
+			}
+		})
+	}
+}
+
+func TestTrustedTypes_Sources(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		trustedTypes    *TrustedTypes
+		expectedSources []CSPSource
+	}{
+		{name: "None", trustedTypes: NewTrustedTypes(), expectedSources: []CSPSource{SourceNone}},
+		{name: "None, Allow Duplicates", trustedTypes: &TrustedTypes{AllowDuplicates: true}, expectedSources: []CSPSource{SourceNone}},
+		{name: "Policies", trustedTypes: NewTrustedTypes("default", "dompurify"), expectedSources: []CSPSource{"default", "dompurify"}},
+		{
+			name:            "Policies, Allow Duplicates",
+			trustedTypes:    &TrustedTypes{Policies: []string{"default"}, AllowDuplicates: true},
+			expectedSources: []CSPSource{"default", TrustedTypesAllowDuplicates},
+		},
+		{name: "Any Policy", trustedTypes: &TrustedTypes{AllowAnyPolicy: true}, expectedSources: []CSPSource{SourceWildcard}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sources := tc.trustedTypes.Sources()
+			if !reflect.DeepEqual(sources, tc.expectedSources) {
+				t.Errorf("Expected: %v\tActual: %v\n", tc.expectedSources, sources)
+			}
+		})
+	}
+}
+
+func TestTrustedTypes_Apply(t *testing.T) {
+	t.Parallel()
+
+	csp := NewContentSecurityPolicy(map[CSPDirective][]CSPSource{
+		DirectiveTrustedTypes: {"stale"},
+	})
+
+	NewTrustedTypes("default").Apply(csp)
+	expected := map[CSPDirective][]CSPSource{
+		DirectiveTrustedTypes:           {"default"},
+		DirectiveRequireTrustedTypesFor: {RequireTrustedTypesForScript},
+	}
+	if !reflect.DeepEqual(csp.policies, expected) {
+		t.Errorf("Expected: %v\tActual: %v\n", expected, csp.policies)
+	}
+
+	(&TrustedTypes{Policies: []string{"default"}}).Apply(csp)
+	expected = map[CSPDirective][]CSPSource{DirectiveTrustedTypes: {"default"}}
+	if !reflect.DeepEqual(csp.policies, expected) {
+		t.Errorf("Expected: %v\tActual: %v\n", expected, csp.policies)
+	}
+}
+
+func TestHelmet_SetTrustedTypes(t *testing.T) {
+	t.Parallel()
+
+	helmet := Default()
+	helmet.ContentSecurityPolicy.Add(DirectiveDefaultSrc, SourceSelf)
+	helmet.ContentSecurityPolicy.Add(DeprecatedDirectiveReportURI, "/csp-reports")
+
+	if err := helmet.SetTrustedTypes(NewTrustedTypes("my policy"), true); err == nil {
+		t.Errorf("Invalid policy names should be rejected\n")
+	}
+
+	// roll out in report-only mode first
+	if err := helmet.SetTrustedTypes(NewTrustedTypes("default"), true); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	rr, r := newRecorderRequest(t)
+	helmet.Secure(mockNext).ServeHTTP(rr, r)
+	resp := rr.Result()
+
+	reportOnly := ParseContentSecurityPolicy(resp.Header.Get(HeaderContentSecurityPolicyReportOnly))
+	expected := map[CSPDirective][]CSPSource{
+		DirectiveTrustedTypes:           {"default"},
+		DirectiveRequireTrustedTypesFor: {RequireTrustedTypesForScript},
+		DeprecatedDirectiveReportURI:    {"/csp-reports"},
+	}
+	if !reflect.DeepEqual(reportOnly.policies, expected) {
+		t.Errorf("Incorrect %s\tExpected: %v\tActual: %v\n", HeaderContentSecurityPolicyReportOnly, expected, reportOnly.policies)
+	}
+
+	// then enforce it
+	if err := helmet.SetTrustedTypes(NewTrustedTypes("default"), false); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	if _, ok := helmet.ContentSecurityPolicyReportOnly.policies[DirectiveTrustedTypes]; ok {
+		t.Errorf("Enforcing should remove the report-only Trusted Types\n")
+	}
+
+	if _, ok := helmet.ContentSecurityPolicy.policies[DirectiveRequireTrustedTypesFor]; !ok {
+		t.Errorf("Enforcing should add require-trusted-types-for\n")
+	}
+}
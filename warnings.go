@@ -0,0 +1,74 @@
+package helmet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Warning represents a Helmet configuration that is valid, but probably not what was intended.
+type Warning struct {
+	Module  string // name of the Module the Warning is about
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Module, w.Message)
+}
+
+// Warnings returns every Warning about the Helmet's configuration.
+func (h *Helmet) Warnings() []Warning {
+	warnings := []Warning{}
+	warnings = append(warnings, h.corsWarnings()...)
+	warnings = append(warnings, h.framingWarnings()...)
+	warnings = append(warnings, h.sandboxWarnings()...)
+	warnings = append(warnings, h.deprecationWarnings()...)
+	warnings = append(warnings, h.xxssProtectionWarnings()...)
+	return warnings
+}
+
+// xxssProtectionWarnings flags an X-XSS-Protection whose invalid directives are dropped from the header.
+func (h *Helmet) xxssProtectionWarnings() []Warning {
+	if h.XXSSProtection == nil {
+		return nil
+	}
+
+	if err := h.XXSSProtection.Validate(); err != nil {
+		return []Warning{{HeaderXXSSProtection, strings.TrimPrefix(err.Error(), "helmet: ") + ", so it is left out of the header"}}
+	}
+	return nil
+}
+
+// corsWarnings flags CORS settings that other cross-origin headers will undermine.
+func (h *Helmet) corsWarnings() []Warning {
+	if h.CORS == nil || !h.CORS.AllowsCrossOrigin() {
+		return nil
+	}
+
+	warnings := []Warning{}
+
+	if h.CORS.AllowCredentials {
+		for _, origin := range h.CORS.AllowedOrigins {
+			if origin == CORSOriginWildcard {
+				warnings = append(warnings, Warning{ModuleCORS, "credentials are never allowed for origins that only the wildcard allows, list the origins that need them explicitly"})
+				break
+			}
+		}
+	}
+
+	switch h.CrossOriginResourcePolicy {
+	case CrossOriginResourcePolicySameOrigin, CrossOriginResourcePolicySameSite:
+		warnings = append(warnings, Warning{
+			ModuleCORS,
+			fmt.Sprintf("CORS allows other origins, but %s %s still blocks them from loading resources without CORS (e.g. <img> or <script> without crossorigin)", HeaderCrossOriginResourcePolicy, h.CrossOriginResourcePolicy),
+		})
+	case "":
+		if h.CrossOriginEmbedderPolicy == CrossOriginEmbedderPolicyRequireCorp {
+			warnings = append(warnings, Warning{
+				ModuleCORS,
+				fmt.Sprintf("CORS allows other origins, but without %s they can't embed resources without CORS under %s %s", HeaderCrossOriginResourcePolicy, HeaderCrossOriginEmbedderPolicy, CrossOriginEmbedderPolicyRequireCorp),
+			})
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,93 @@
+package helmet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelmet_Warnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		configure        func(h *Helmet)
+		expectedWarnings []string // substrings, one per Warning, in order
+	}{
+		{name: "Default", configure: func(h *Helmet) {}, expectedWarnings: []string{}},
+		{
+			name: "CORS Without Cross-Origin Headers",
+			configure: func(h *Helmet) {
+				h.CORS = NewCORS("https://example.com")
+			},
+			expectedWarnings: []string{},
+		},
+		{
+			name: "CORS, CORP Same Origin",
+			configure: func(h *Helmet) {
+				h.CORS = NewCORS("https://example.com")
+				h.CrossOriginResourcePolicy = CrossOriginResourcePolicySameOrigin
+			},
+			expectedWarnings: []string{"Cross-Origin-Resource-Policy same-origin"},
+		},
+		{
+			name: "CORS, CORP Same Site",
+			configure: func(h *Helmet) {
+				h.CORS = NewCORS("https://example.com")
+				h.CrossOriginResourcePolicy = CrossOriginResourcePolicySameSite
+			},
+			expectedWarnings: []string{"Cross-Origin-Resource-Policy same-site"},
+		},
+		{
+			name: "CORS, CORP Cross Origin",
+			configure: func(h *Helmet) {
+				h.CORS = NewCORS("https://example.com")
+				h.CrossOriginResourcePolicy = CrossOriginResourcePolicyCrossOrigin
+				h.CrossOriginEmbedderPolicy = CrossOriginEmbedderPolicyRequireCorp
+			},
+			expectedWarnings: []string{},
+		},
+		{
+			name: "CORS, COEP Without CORP",
+			configure: func(h *Helmet) {
+				h.CORS = NewCORS("https://example.com")
+				h.CrossOriginEmbedderPolicy = CrossOriginEmbedderPolicyRequireCorp
+			},
+			expectedWarnings: []string{"Cross-Origin-Embedder-Policy require-corp"},
+		},
+		{
+			name: "X-XSS-Protection, Report Without XSS Filtering",
+			configure: func(h *Helmet) {
+				h.XXSSProtection = NewXXSSProtection(false, "", "/report-uri")
+			},
+			expectedWarnings: []string{"report requires XSS filtering"},
+		},
+		{
+			name: "CORS, Wildcard With Credentials",
+			configure: func(h *Helmet) {
+				h.CORS = &CORS{AllowedOrigins: []string{CORSOriginWildcard}, AllowCredentials: true}
+			},
+			expectedWarnings: []string{"credentials"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			helmet := Default()
+			tc.configure(helmet)
+
+			warnings := helmet.Warnings()
+			if len(warnings) != len(tc.expectedWarnings) {
+				t.Fatalf("Incorrect warnings\tExpected: %v\tActual: %v\n", tc.expectedWarnings, warnings)
+			}
+
+			for i, warning := range warnings {
+				if !strings.Contains(warning.String(), tc.expectedWarnings[i]) {
+					t.Errorf("Incorrect warning\tExpected: %s\tActual: %s\n", tc.expectedWarnings[i], warning)
+				}
+			}
+		})
+	}
+}
@@ -20,9 +20,15 @@ func (xcto XContentTypeOptions) Empty() bool {
 	return xcto.String() == ""
 }
 
-// Header adds the X-Content-Type-Options HTTP security header to the given http.ResponseWriter.
-func (xcto XContentTypeOptions) Header(w http.ResponseWriter) {
+// HeaderFor adds the X-Content-Type-Options HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (xcto XContentTypeOptions) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !xcto.Empty() {
 		w.Header().Set(HeaderXContentTypeOptions, xcto.String())
 	}
 }
+
+// Header adds the X-Content-Type-Options HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (xcto XContentTypeOptions) Header(w http.ResponseWriter) {
+	xcto.HeaderFor(w, nil)
+}
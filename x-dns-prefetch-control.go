@@ -23,9 +23,15 @@ func (dns XDNSPrefetchControl) Empty() bool {
 	return dns.String() == ""
 }
 
-// Header adds the X-DNS-Prefetch-Control HTTP security header to the given http.ResponseWriter.
-func (dns XDNSPrefetchControl) Header(w http.ResponseWriter) {
+// HeaderFor adds the X-DNS-Prefetch-Control HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (dns XDNSPrefetchControl) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !dns.Empty() {
 		w.Header().Set(HeaderXDNSPrefetchControl, dns.String())
 	}
 }
+
+// Header adds the X-DNS-Prefetch-Control HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (dns XDNSPrefetchControl) Header(w http.ResponseWriter) {
+	dns.HeaderFor(w, nil)
+}
@@ -20,9 +20,15 @@ func (xdo XDownloadOptions) Empty() bool {
 	return xdo.String() == ""
 }
 
-// Header adds the X-Download-Options HTTP security header to the given http.ResponseWriter.
-func (xdo XDownloadOptions) Header(w http.ResponseWriter) {
+// HeaderFor adds the X-Download-Options HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (xdo XDownloadOptions) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !xdo.Empty() {
 		w.Header().Set(HeaderXDownloadOptions, xdo.String())
 	}
 }
+
+// Header adds the X-Download-Options HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (xdo XDownloadOptions) Header(w http.ResponseWriter) {
+	xdo.HeaderFor(w, nil)
+}
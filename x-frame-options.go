@@ -23,9 +23,15 @@ func (xfo XFrameOptions) Empty() bool {
 	return xfo.String() == ""
 }
 
-// Header adds the X-Frame-Options HTTP header to the given http.ResponseWriter.
-func (xfo XFrameOptions) Header(w http.ResponseWriter) {
+// HeaderFor adds the X-Frame-Options HTTP header for the given http.Request to the given http.ResponseWriter.
+func (xfo XFrameOptions) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !xfo.Empty() {
 		w.Header().Set(HeaderXFrameOptions, xfo.String())
 	}
 }
+
+// Header adds the X-Frame-Options HTTP header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (xfo XFrameOptions) Header(w http.ResponseWriter) {
+	xfo.HeaderFor(w, nil)
+}
@@ -26,9 +26,15 @@ func (cdp XPermittedCrossDomainPolicies) Empty() bool {
 	return cdp.String() == ""
 }
 
-// Header adds the X-DNS-Prefetch-Control HTTP security header to the given http.ResponseWriter.
-func (cdp XPermittedCrossDomainPolicies) Header(w http.ResponseWriter) {
+// HeaderFor adds the X-Permitted-Cross-Domain-Policies HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (cdp XPermittedCrossDomainPolicies) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !cdp.Empty() {
 		w.Header().Set(HeaderXPermittedCrossDomainPolicies, cdp.String())
 	}
 }
+
+// Header adds the X-DNS-Prefetch-Control HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (cdp XPermittedCrossDomainPolicies) Header(w http.ResponseWriter) {
+	cdp.HeaderFor(w, nil)
+}
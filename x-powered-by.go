@@ -29,8 +29,8 @@ func (xpb XPoweredBy) Empty() bool {
 	return !xpb.Hide && xpb.Replacement == ""
 }
 
-// Header adds the X-Powered-By HTTP security header to the given http.ResponseWriter.
-func (xpb XPoweredBy) Header(w http.ResponseWriter) {
+// HeaderFor adds the X-Powered-By HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (xpb XPoweredBy) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if xpb.Empty() {
 		return
 	}
@@ -44,3 +44,9 @@ func (xpb XPoweredBy) Header(w http.ResponseWriter) {
 		w.Header().Set(HeaderXPoweredBy, xpb.Replacement)
 	}
 }
+
+// Header adds the X-Powered-By HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (xpb XPoweredBy) Header(w http.ResponseWriter) {
+	xpb.HeaderFor(w, nil)
+}
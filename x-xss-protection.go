@@ -33,10 +33,13 @@ type (
 	XXSSProtectionDirective string
 
 	// XXSSProtection represents the X-XSS-Protection HTTP security header.
+	//
+	// The XSS auditor it controls has been removed from modern browsers, partly because the auditor itself
+	// could be abused to leak information across origins, so disabling it is the only recommended setting.
 	XXSSProtection struct {
-		XSSFiltering bool
-		Mode         XXSSProtectionDirective
-		ReportURI    string
+		XSSFiltering bool                    // whether the XSS auditor is enabled at all
+		Mode         XXSSProtectionDirective // either empty, to sanitize the page, or DirectiveModeBlock, to not render it
+		ReportURI    string                  // where violations are reported, only valid while filtering
 
 		cache string
 	}
@@ -56,6 +59,44 @@ func EmptyXXSSProtection() *XXSSProtection {
 	return NewXXSSProtection(false, "", "")
 }
 
+// DisabledXXSSProtection creates a new X-XSS-Protection that disables the XSS auditor: "0".
+func DisabledXXSSProtection() *XXSSProtection {
+	return NewXXSSProtection(false, "", "")
+}
+
+// FilterXXSSProtection creates a new X-XSS-Protection that sanitizes pages when an attack is detected: "1".
+func FilterXXSSProtection() *XXSSProtection {
+	return NewXXSSProtection(true, "", "")
+}
+
+// BlockXXSSProtection creates a new X-XSS-Protection that stops rendering pages when an attack is detected: "1; mode=block".
+func BlockXXSSProtection() *XXSSProtection {
+	return NewXXSSProtection(true, DirectiveModeBlock, "")
+}
+
+// ReportXXSSProtection creates a new X-XSS-Protection that sanitizes pages and reports when an attack is detected: "1; report=<reportURI>".
+func ReportXXSSProtection(reportURI string) *XXSSProtection {
+	return NewXXSSProtection(true, "", reportURI)
+}
+
+// Validate returns an error if the X-XSS-Protection combines directives that browsers would not honour.
+// String drops those directives, and Helmet.Warnings reports the error.
+func (xssp *XXSSProtection) Validate() error {
+	if xssp.Mode != "" && xssp.Mode != DirectiveModeBlock {
+		return fmt.Errorf("helmet: invalid X-XSS-Protection mode %q", xssp.Mode)
+	}
+
+	if !xssp.XSSFiltering && xssp.Mode != "" {
+		return fmt.Errorf("helmet: X-XSS-Protection %s requires XSS filtering", xssp.Mode)
+	}
+
+	if !xssp.XSSFiltering && xssp.ReportURI != "" {
+		return fmt.Errorf("helmet: X-XSS-Protection report requires XSS filtering")
+	}
+
+	return nil
+}
+
 func (xssp *XXSSProtection) String() string {
 	if len(xssp.cache) != 0 {
 		return xssp.cache
@@ -65,11 +106,12 @@ func (xssp *XXSSProtection) String() string {
 		string(XXSSProtectionDirectiveXSSFiltering(xssp.XSSFiltering)),
 	}
 
-	if xssp.Mode != "" {
+	// directives only apply while filtering, and block is the only mode there is
+	if xssp.XSSFiltering && xssp.Mode == DirectiveModeBlock {
 		builder = append(builder, string(DirectiveModeBlock))
 	}
 
-	if xssp.ReportURI != "" {
+	if xssp.XSSFiltering && xssp.ReportURI != "" {
 		builder = append(builder, string(XXSSProtectionDirectiveReportURI(xssp.ReportURI)))
 	}
 
@@ -84,9 +126,24 @@ func (xssp *XXSSProtection) Empty() bool {
 	return false
 }
 
-// Header adds the X-XSS-Protection HTTP security header to the given http.ResponseWriter.
-func (xssp *XXSSProtection) Header(w http.ResponseWriter) {
+// Deprecation returns why the X-XSS-Protection is deprecated: browsers removed the XSS auditor.
+// Disabling the auditor is still supported, so it returns nil unless XSS filtering is on.
+func (xssp *XXSSProtection) Deprecation() *Deprecation {
+	if !xssp.XSSFiltering {
+		return nil
+	}
+	return &Deprecation{Since: "Chrome 78", ReplacedBy: HeaderContentSecurityPolicy}
+}
+
+// HeaderFor adds the X-XSS-Protection HTTP security header for the given http.Request to the given http.ResponseWriter.
+func (xssp *XXSSProtection) HeaderFor(w http.ResponseWriter, r *http.Request) {
 	if !xssp.Empty() {
 		w.Header().Set(HeaderXXSSProtection, xssp.String())
 	}
 }
+
+// Header adds the X-XSS-Protection HTTP security header to the given http.ResponseWriter.
+// It is kept for compatibility, and is equivalent to HeaderFor without an http.Request.
+func (xssp *XXSSProtection) Header(w http.ResponseWriter) {
+	xssp.HeaderFor(w, nil)
+}
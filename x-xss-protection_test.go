@@ -137,6 +137,20 @@ func TestXXSSProtection_String(t *testing.T) {
 			xXSSProtection: NewXXSSProtection(true, DirectiveModeBlock, "/report-uri"),
 			expectedHeader: "1; mode=block; report=/report-uri",
 		},
+		{
+			name:           "XSS Filtering, Unknown Mode",
+			xXSSProtection: NewXXSSProtection(true, "mode=sanitize", ""),
+			expectedHeader: "1",
+		},
+		{
+			name:           "No XSS Filtering, Mode Block, Report URI",
+			xXSSProtection: NewXXSSProtection(false, DirectiveModeBlock, "/report-uri"),
+			expectedHeader: "0",
+		},
+		{name: "Disabled", xXSSProtection: DisabledXXSSProtection(), expectedHeader: "0"},
+		{name: "Filter", xXSSProtection: FilterXXSSProtection(), expectedHeader: "1"},
+		{name: "Block", xXSSProtection: BlockXXSSProtection(), expectedHeader: "1; mode=block"},
+		{name: "Report", xXSSProtection: ReportXXSSProtection("/report-uri"), expectedHeader: "1; report=/report-uri"},
 	}
 
 	for _, tc := range testCases {
@@ -163,6 +177,53 @@ func TestXXSSProtection_String(t *testing.T) {
 	}
 }
 
+func TestXXSSProtection_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		xXSSProtection *XXSSProtection
+		expectedValid  bool
+	}{
+		{name: "Disabled", xXSSProtection: DisabledXXSSProtection(), expectedValid: true},
+		{name: "Filter", xXSSProtection: FilterXXSSProtection(), expectedValid: true},
+		{name: "Block", xXSSProtection: BlockXXSSProtection(), expectedValid: true},
+		{name: "Report", xXSSProtection: ReportXXSSProtection("/report-uri"), expectedValid: true},
+		{
+			name:           "Block, Report URI",
+			xXSSProtection: NewXXSSProtection(true, DirectiveModeBlock, "/report-uri"),
+			expectedValid:  true,
+		},
+		{
+			name:           "Report URI without XSS Filtering",
+			xXSSProtection: NewXXSSProtection(false, "", "/report-uri"),
+			expectedValid:  false,
+		},
+		{
+			name:           "Mode Block without XSS Filtering",
+			xXSSProtection: NewXXSSProtection(false, DirectiveModeBlock, ""),
+			expectedValid:  false,
+		},
+		{
+			name:           "Unknown Mode",
+			xXSSProtection: NewXXSSProtection(true, "mode=sanitize", ""),
+			expectedValid:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.xXSSProtection.Validate()
+			if (err == nil) != tc.expectedValid {
+				t.Errorf("Expected valid: %t\tActual error: %v\n", tc.expectedValid, err)
+			}
+		})
+	}
+}
+
 func TestXXSSProtection_Empty(t *testing.T) {
 	t.Parallel()
 